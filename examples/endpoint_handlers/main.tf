@@ -34,3 +34,20 @@ resource "stacuity_event_handler" "test_event_handler_basic" {
   }
   event_endpoint_type = "webhook"
 }
+
+# Terraform 1.11+ can send secrets as write-only arguments, which are never stored in state.
+# Bump password_wo_version to send a new password.
+resource "stacuity_event_handler" "test_event_handler_write_only" {
+  name    = "terraform webhook write-only"
+  moniker = "tf-webhook-wo"
+  configuration_data = {
+    webhook_config = {
+      url                 = "https://terraform.com",
+      timeout             = "10",
+      username            = "my username",
+      password_wo         = "my password",
+      password_wo_version = 1
+    }
+  }
+  event_endpoint_type = "webhook"
+}
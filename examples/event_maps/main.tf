@@ -37,3 +37,9 @@ resource "stacuity_event_map" "test_event_map_basic_2" {
   moniker     = "tf-event-map-2"
   event_scope = "vslice"
 }
+
+resource "stacuity_event_subscription" "test_event_map_basic_2_webhook" {
+  event_map     = stacuity_event_map.test_event_map_basic_2.moniker
+  event_handler = "tf-webhook"
+  event_type    = "vpnchildsaphase2up_v1"
+}
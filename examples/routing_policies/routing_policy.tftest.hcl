@@ -0,0 +1,31 @@
+# Copyright (c) HashiCorp, Inc.
+
+# Assert routing policy behaviour with `terraform test`. Rules are evaluated locally by
+# provider::stacuity::evaluate_routing_policy, so a plan is enough.
+run "uplink_to_blocked_host_is_dropped" {
+  command = plan
+
+  assert {
+    condition = provider::stacuity::evaluate_routing_policy(stacuity_routing_policy.test_routing_policy_onerule.routing_policy_rules, {
+      direction        = "uplink"
+      protocol         = "tcp"
+      source_ip        = "10.0.0.1"
+      destination_ip   = "4.3.2.1"
+      source_port      = 40000
+      destination_port = 443
+    }).rule_action == "drop"
+    error_message = "Uplink traffic to 4.3.2.1 should be dropped."
+  }
+
+  assert {
+    condition = !provider::stacuity::evaluate_routing_policy(stacuity_routing_policy.test_routing_policy_onerule.routing_policy_rules, {
+      direction        = "uplink"
+      protocol         = "tcp"
+      source_ip        = "10.0.0.1"
+      destination_ip   = "1.1.1.1"
+      source_port      = 40000
+      destination_port = 443
+    }).matched
+    error_message = "Uplink traffic to other hosts should not match any rule."
+  }
+}
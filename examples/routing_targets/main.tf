@@ -70,3 +70,54 @@ resource "stacuity_routing_target" "test_routing_target_wireguard" {
   routing_target_type_instance_id = "ma5-prod-vpn-01a-wg"
 }
 
+
+# Terraform 1.11+: generate the customer-side key pair without writing it to state. A new pair is
+# generated on every run, but the public key is only sent when remote_public_key_wo_version changes.
+ephemeral "stacuity_wireguard_key_pair" "customer_peer" {}
+
+resource "stacuity_routing_target" "test_routing_target_wireguard_generated_key" {
+  name                    = "Terraform Wireguard Target Generated Key"
+  moniker                 = "tf-wireguard-gen"
+  redundancy_zone_moniker = "europe-primary"
+  configuration_data = {
+    wireguard_config = {
+      local_subnets                = "10.0.0.0/8"
+      remote_public_key_wo         = ephemeral.stacuity_wireguard_key_pair.customer_peer.public_key
+      remote_public_key_wo_version = 1
+      remote_subnets               = "192.168.0.0/16"
+      remote_peer_ip_address       = "192.168.1.5"
+    }
+  }
+  vslice                          = "tf-test" //use one that already exists
+  routing_target_type             = "wireguard"
+  routing_target_type_instance_id = "ma5-prod-vpn-01a-wg"
+}
+
+# Render wg0.conf for the customer-side peer. Without private_key a placeholder is rendered, so the
+# private key never passes through Terraform.
+data "stacuity_wireguard_peer_config" "customer_peer" {
+  routing_target       = stacuity_routing_target.test_routing_target_wireguard.moniker
+  address              = "192.168.1.5/32"
+  endpoint             = "vpn.example.com"
+  persistent_keepalive = 25
+}
+
+output "wg0_conf" {
+  value     = data.stacuity_wireguard_peer_config.customer_peer.config
+  sensitive = true
+}
+
+# Render the customer-side IPsec configuration of the VPN routing target.
+data "stacuity_ipsec_peer_config" "customer_peer" {
+  routing_target   = stacuity_routing_target.test_routing_target_vpn.moniker
+  stacuity_address = "vpn.example.com"
+}
+
+output "ipsec_summary" {
+  value = data.stacuity_ipsec_peer_config.customer_peer.summary
+}
+
+output "swanctl_conf" {
+  value     = data.stacuity_ipsec_peer_config.customer_peer.swanctl_conf
+  sensitive = true
+}
@@ -0,0 +1,17 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// addChangedSincePlanError reports an update the API rejected because the object was changed
+// outside of Terraform between plan and apply.
+func addChangedSincePlanError(diags *diag.Diagnostics, objectType, moniker string) {
+	diags.AddError(
+		"Object changed since plan",
+		"The "+objectType+" "+moniker+" was changed outside of Terraform after the plan was created, so it was not updated. "+
+			"Run terraform plan again to review the changes against its current settings, then apply.",
+	)
+}
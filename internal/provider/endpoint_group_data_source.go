@@ -60,6 +60,7 @@ func (d *endpointGroupDataSource) Metadata(_ context.Context, req datasource.Met
 type endpointGroupsDataSourceModel struct {
 	EndpointGroups []endpointGroupReadModel `tfsdk:"endpointgroups"`
 	Filter         types.Object             `tfsdk:"filter"`
+	All            types.Bool               `tfsdk:"all"`
 }
 
 // endpointGroupReadModel maps schema data.
@@ -219,6 +220,10 @@ func (d *endpointGroupDataSource) Schema(_ context.Context, _ datasource.SchemaR
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -262,7 +267,12 @@ func (d *endpointGroupDataSource) Read(ctx context.Context, req datasource.ReadR
 		}
 	}
 
-	routingPolicies, err := d.client.GetEndpointGroups(pagingQuery)
+	getEndpointGroups := d.client.GetEndpointGroups
+	if state.All.ValueBool() {
+		getEndpointGroups = d.client.GetAllEndpointGroups
+	}
+
+	routingPolicies, err := getEndpointGroups(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity endpoint groups",
@@ -38,6 +38,7 @@ type endpointGroupResource struct {
 
 type endpointGroupResourceModel struct {
 	Id                    types.String `tfsdk:"id"`
+	Version               types.String `tfsdk:"version"`
 	Name                  types.String `tfsdk:"name"`
 	Moniker               types.String `tfsdk:"moniker"`
 	VSlice                types.String `tfsdk:"vslice"`
@@ -49,8 +50,10 @@ type endpointGroupResourceModel struct {
 }
 
 func (r *endpointGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "endpoint group", r.client.GetEndpointGroup, func(item models.EndpointGroupReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *endpointGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -70,6 +73,13 @@ func (r *endpointGroupResource) Schema(ctx context.Context, req resource.SchemaR
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the endpoint group as last read from the API. Updates are rejected if the endpoint group has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the Endpoint Group.",
 				Required:    true,
@@ -156,8 +166,9 @@ func (r *endpointGroupResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	// Create new endpointGroup
-	createResponse, err := r.client.CreateEndpointGroup(apiData)
+	// Create new endpointGroup, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	createResponse, err := r.client.CreateEndpointGroup(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating endpoint group",
@@ -167,7 +178,7 @@ func (r *endpointGroupResource) Create(ctx context.Context, req resource.CreateR
 	}
 
 	if createResponse.Success {
-		getResponse, err := r.client.GetEndpointGroup(createResponse.Data)
+		getResponse, err := r.client.GetEndpointGroup(ctx, createResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading endpoint group",
@@ -221,12 +232,14 @@ func (r *endpointGroupResource) Read(ctx context.Context, req resource.ReadReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
+
 	// Get refreshed endpointGroup values
-	apiResponse, err := r.client.GetEndpointGroup(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetEndpointGroup(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -289,9 +302,19 @@ func (r *endpointGroupResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	// Update existing endpointGroup
-	_, err = r.client.UpdateEndpointGroup(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Update existing endpointGroup, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+	_, err = r.client.UpdateEndpointGroup(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "endpoint group", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating endpoint group Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update endpoint group, unexpected error: "+err.Error(),
@@ -299,9 +322,12 @@ func (r *endpointGroupResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
 	// Fetch updated endpointGroup to update state
 	// populated.
-	apiResponse, err := r.client.GetEndpointGroup(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetEndpointGroup(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading endpoint group Info",
@@ -351,11 +377,17 @@ func (r *endpointGroupResource) Delete(ctx context.Context, req resource.DeleteR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing endpointGroup
-	result, err := r.client.DeleteEndpointGroup(state.Moniker.ValueString())
+	result, err := r.client.DeleteEndpointGroup(ctx, key)
 
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting endpoint group",
 			"Could not delete endpoint group, unexpected error: "+err.Error(),
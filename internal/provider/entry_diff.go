@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"strconv"
+)
+
+// entryChange - An existing child entry that must be replaced with a new value
+type entryChange[Desired any] struct {
+	Id    string
+	Entry Desired
+}
+
+// entryDiff - The calls needed to turn the child entries of an object, such as the entries of a
+// policy or the subscriptions of an event map, into the configured ones
+type entryDiff[Desired any] struct {
+	Add    []Desired
+	Change []entryChange[Desired]
+	Remove []string
+}
+
+// Empty reports whether the entries already match.
+func (d entryDiff[Desired]) Empty() bool {
+	return len(d.Add) == 0 && len(d.Change) == 0 && len(d.Remove) == 0
+}
+
+// entryKeys - How diffEntries identifies entries on each side and compares their values
+type entryKeys[Current any, Desired any] struct {
+	current func(Current) string
+	desired func(Desired) string
+	id      func(Current) string
+	// same reports whether an existing entry already has the desired value. Nil means entries
+	// with matching keys never need changing.
+	same func(Current, Desired) bool
+}
+
+// diffEntries matches current and desired entries by key. Current entries without a desired
+// counterpart are removed, including duplicates of a key left behind by earlier applies, and
+// desired entries without a current counterpart are added.
+func diffEntries[Current any, Desired any](current []Current, desired []Desired, keys entryKeys[Current, Desired]) entryDiff[Desired] {
+	diff := entryDiff[Desired]{}
+
+	// Index of the first current entry with each key.
+	existing := map[string]int{}
+	for i, entry := range current {
+		key := keys.current(entry)
+		if _, duplicate := existing[key]; duplicate {
+			diff.Remove = append(diff.Remove, keys.id(entry))
+			continue
+		}
+		existing[key] = i
+	}
+
+	wanted := map[string]bool{}
+	for _, entry := range desired {
+		key := keys.desired(entry)
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+
+		index, found := existing[key]
+		switch {
+		case !found:
+			diff.Add = append(diff.Add, entry)
+		case keys.same != nil && !keys.same(current[index], entry):
+			diff.Change = append(diff.Change, entryChange[Desired]{Id: keys.id(current[index]), Entry: entry})
+		}
+	}
+
+	for i, entry := range current {
+		key := keys.current(entry)
+		if !wanted[key] && existing[key] == i {
+			diff.Remove = append(diff.Remove, keys.id(entry))
+		}
+	}
+
+	return diff
+}
+
+// countryOperatorKey identifies a policy entry by its country and operator, either of which may be unset.
+func countryOperatorKey(iso3 *string, operatorId *int32) string {
+	key := "*"
+	if iso3 != nil {
+		key = *iso3
+	}
+
+	if operatorId != nil {
+		return key + "/" + strconv.Itoa(int(*operatorId))
+	}
+
+	return key + "/*"
+}
+
+// subscriptionKey identifies an event map subscription by its handler and event type.
+func subscriptionKey(handler, eventType string) string {
+	return handler + "/" + eventType
+}
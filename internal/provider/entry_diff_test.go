@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testCurrentEntry struct {
+	id     string
+	key    string
+	action string
+}
+
+type testDesiredEntry struct {
+	key    string
+	action string
+}
+
+var testEntryKeys = entryKeys[testCurrentEntry, testDesiredEntry]{
+	current: func(entry testCurrentEntry) string { return entry.key },
+	desired: func(entry testDesiredEntry) string { return entry.key },
+	id:      func(entry testCurrentEntry) string { return entry.id },
+	same: func(current testCurrentEntry, desired testDesiredEntry) bool {
+		return current.action == desired.action
+	},
+}
+
+func TestDiffEntries(t *testing.T) {
+	tests := map[string]struct {
+		current []testCurrentEntry
+		desired []testDesiredEntry
+		keys    entryKeys[testCurrentEntry, testDesiredEntry]
+		want    entryDiff[testDesiredEntry]
+	}{
+		"no entries": {
+			keys: testEntryKeys,
+			want: entryDiff[testDesiredEntry]{},
+		},
+		"no-op": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}, {id: "2", key: "FRA/*", action: "deny"}},
+			desired: []testDesiredEntry{{key: "FRA/*", action: "deny"}, {key: "GBR/*", action: "allow"}},
+			keys:    testEntryKeys,
+			want:    entryDiff[testDesiredEntry]{},
+		},
+		"add only": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}},
+			desired: []testDesiredEntry{{key: "GBR/*", action: "allow"}, {key: "FRA/*", action: "deny"}, {key: "*/7", action: "allow"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Add: []testDesiredEntry{{key: "FRA/*", action: "deny"}, {key: "*/7", action: "allow"}},
+			},
+		},
+		"add to empty": {
+			desired: []testDesiredEntry{{key: "GBR/*", action: "allow"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Add: []testDesiredEntry{{key: "GBR/*", action: "allow"}},
+			},
+		},
+		"remove only": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}, {id: "2", key: "FRA/*", action: "deny"}, {id: "3", key: "*/7", action: "allow"}},
+			desired: []testDesiredEntry{{key: "FRA/*", action: "deny"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Remove: []string{"1", "3"},
+			},
+		},
+		"remove all": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}, {id: "2", key: "FRA/*", action: "deny"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Remove: []string{"1", "2"},
+			},
+		},
+		"changed entries": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}, {id: "2", key: "FRA/*", action: "deny"}},
+			desired: []testDesiredEntry{{key: "GBR/*", action: "deny"}, {key: "FRA/*", action: "deny"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Change: []entryChange[testDesiredEntry]{{Id: "1", Entry: testDesiredEntry{key: "GBR/*", action: "deny"}}},
+			},
+		},
+		"changed entries without a comparison": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}},
+			desired: []testDesiredEntry{{key: "GBR/*", action: "deny"}},
+			keys:    entryKeys[testCurrentEntry, testDesiredEntry]{current: testEntryKeys.current, desired: testEntryKeys.desired, id: testEntryKeys.id},
+			want:    entryDiff[testDesiredEntry]{},
+		},
+		"add, change and remove": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}, {id: "2", key: "FRA/*", action: "deny"}},
+			desired: []testDesiredEntry{{key: "GBR/*", action: "deny"}, {key: "DEU/*", action: "allow"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Add:    []testDesiredEntry{{key: "DEU/*", action: "allow"}},
+				Change: []entryChange[testDesiredEntry]{{Id: "1", Entry: testDesiredEntry{key: "GBR/*", action: "deny"}}},
+				Remove: []string{"2"},
+			},
+		},
+		"duplicate current entries": {
+			current: []testCurrentEntry{{id: "1", key: "GBR/*", action: "allow"}, {id: "2", key: "GBR/*", action: "allow"}, {id: "3", key: "GBR/*", action: "deny"}},
+			desired: []testDesiredEntry{{key: "GBR/*", action: "allow"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Remove: []string{"2", "3"},
+			},
+		},
+		"duplicate desired entries": {
+			desired: []testDesiredEntry{{key: "GBR/*", action: "allow"}, {key: "GBR/*", action: "deny"}},
+			keys:    testEntryKeys,
+			want: entryDiff[testDesiredEntry]{
+				Add: []testDesiredEntry{{key: "GBR/*", action: "allow"}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diffEntries(test.current, test.desired, test.keys)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+			if got.Empty() != (len(test.want.Add) == 0 && len(test.want.Change) == 0 && len(test.want.Remove) == 0) {
+				t.Errorf("Empty() = %t for %+v", got.Empty(), got)
+			}
+		})
+	}
+}
+
+func TestCountryOperatorKey(t *testing.T) {
+	iso3 := "GBR"
+	operatorId := int32(23430)
+
+	tests := map[string]struct {
+		iso3       *string
+		operatorId *int32
+		want       string
+	}{
+		"country and operator": {iso3: &iso3, operatorId: &operatorId, want: "GBR/23430"},
+		"country only":         {iso3: &iso3, want: "GBR/*"},
+		"operator only":        {operatorId: &operatorId, want: "*/23430"},
+		"neither":              {want: "*/*"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := countryOperatorKey(test.iso3, test.operatorId); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
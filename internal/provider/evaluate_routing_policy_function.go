@@ -0,0 +1,388 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"terraform-provider-stacuity/internal/routingpolicy"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ function.Function = &evaluateRoutingPolicyFunction{}
+)
+
+// NewEvaluateRoutingPolicyFunction is a helper function to simplify the provider implementation.
+func NewEvaluateRoutingPolicyFunction() function.Function {
+	return &evaluateRoutingPolicyFunction{}
+}
+
+// evaluateRoutingPolicyFunction is the function implementation. Rules are evaluated locally,
+// so it needs no client.
+type evaluateRoutingPolicyFunction struct{}
+
+// evaluateRoutingPolicyFlowModel maps the flow argument.
+type evaluateRoutingPolicyFlowModel struct {
+	Direction       types.String `tfsdk:"direction"`
+	Protocol        types.String `tfsdk:"protocol"`
+	SourceIp        types.String `tfsdk:"source_ip"`
+	DestinationIp   types.String `tfsdk:"destination_ip"`
+	SourcePort      types.Int64  `tfsdk:"source_port"`
+	DestinationPort types.Int64  `tfsdk:"destination_port"`
+}
+
+// evaluateRoutingPolicyResultModel maps the result.
+type evaluateRoutingPolicyResultModel struct {
+	Matched       types.Bool   `tfsdk:"matched"`
+	Index         types.Int64  `tfsdk:"index"`
+	Precedence    types.Int64  `tfsdk:"precedence"`
+	Description   types.String `tfsdk:"description"`
+	RuleAction    types.String `tfsdk:"rule_action"`
+	RoutingTarget types.String `tfsdk:"routing_target"`
+	Reflected     types.Bool   `tfsdk:"reflected"`
+}
+
+var evaluateRoutingPolicyFlowTypes = map[string]attr.Type{
+	"direction":        types.StringType,
+	"protocol":         types.StringType,
+	"source_ip":        types.StringType,
+	"destination_ip":   types.StringType,
+	"source_port":      types.Int64Type,
+	"destination_port": types.Int64Type,
+}
+
+var evaluateRoutingPolicyResultTypes = map[string]attr.Type{
+	"matched":        types.BoolType,
+	"index":          types.Int64Type,
+	"precedence":     types.Int64Type,
+	"description":    types.StringType,
+	"rule_action":    types.StringType,
+	"routing_target": types.StringType,
+	"reflected":      types.BoolType,
+}
+
+func (f *evaluateRoutingPolicyFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "evaluate_routing_policy"
+}
+
+func (f *evaluateRoutingPolicyFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Finds the routing policy rule that applies to a flow.",
+		MarkdownDescription: "Evaluates routing policy rules against a flow and returns the first enabled rule, in order of precedence, that matches it, " +
+			"with its action and routing target. A reflexive rule also matches the return traffic of the flows it selects. " +
+			"Rules are evaluated locally, so this can be used to assert policy behaviour in `terraform test`.",
+
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name: "rules",
+				MarkdownDescription: "List of rules with the attributes of `routing_policy_rules` in `stacuity_routing_policy`. " +
+					"Rules are evaluated in list order unless they have a `precedence` attribute, as those read by the `stacuity_routing_policies` data source do, " +
+					"whose referenced objects are matched by moniker. " +
+					"Unset or false `enabled` disables a rule, as it does when the rule is sent to the API. " +
+					"The `routing_policy_rules` of a `stacuity_routing_policy` resource are a set, which Terraform does not keep in order, so when more than one of its rules can match a flow pass the list the rules are defined from, or the rules read by the data source, instead.",
+			},
+			function.ObjectParameter{
+				Name: "flow",
+				MarkdownDescription: "The flow to evaluate: `direction` (uplink or downlink), `protocol`, `source_ip`, `destination_ip`, " +
+					"`source_port` and `destination_port`. Leave the protocol and ports null when they do not apply.",
+				AttributeTypes: evaluateRoutingPolicyFlowTypes,
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: evaluateRoutingPolicyResultTypes,
+		},
+	}
+}
+
+func (f *evaluateRoutingPolicyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rulesArgument types.Dynamic
+	var flowArgument evaluateRoutingPolicyFlowModel
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &rulesArgument, &flowArgument))
+	if resp.Error != nil {
+		return
+	}
+
+	rules, err := routingRulesFromDynamic(rulesArgument)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	flow, err := routingPolicyFlow(flowArgument)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	match, matched, err := routingpolicy.Evaluate(rules, flow)
+	if err != nil {
+		var ruleErr *routingpolicy.RuleError
+		if errors.As(err, &ruleErr) {
+			resp.Error = function.NewArgumentFuncError(0, err.Error())
+		} else {
+			resp.Error = function.NewArgumentFuncError(1, err.Error())
+		}
+		return
+	}
+
+	result := evaluateRoutingPolicyResultModel{
+		Matched:       types.BoolValue(matched),
+		Index:         types.Int64Null(),
+		Precedence:    types.Int64Null(),
+		Description:   types.StringNull(),
+		RuleAction:    types.StringNull(),
+		RoutingTarget: types.StringNull(),
+		Reflected:     types.BoolValue(match.Reflected),
+	}
+
+	if matched {
+		result.Index = types.Int64Value(int64(match.Index))
+		result.Precedence = types.Int64Value(int64(match.Rule.Precedence))
+		result.Description = types.StringValue(match.Rule.Description)
+		result.RuleAction = types.StringValue(match.Rule.RuleAction)
+		if match.Rule.RoutingTarget != "" {
+			result.RoutingTarget = types.StringValue(match.Rule.RoutingTarget)
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, &result))
+}
+
+// routingPolicyRule converts a rule to its routingpolicy form.
+func routingPolicyRule(rule RoutingRuleModel, precedence int32) routingpolicy.Rule {
+	return routingpolicy.Rule{
+		Description:            rule.Description.ValueString(),
+		RuleAction:             rule.RuleAction.ValueString(),
+		RuleDirection:          rule.RuleDirection.ValueString(),
+		SourceIpPattern:        rule.SourceIpPattern.ValueString(),
+		DestinationIpPattern:   rule.DestinationIpPattern.ValueString(),
+		TransportProtocol:      rule.TransportProtocol.ValueString(),
+		SourcePortPattern:      rule.SourcePortPattern.ValueString(),
+		DestinationPortPattern: rule.DestinationPortPattern.ValueString(),
+		RoutingTarget:          rule.RoutingTarget.ValueString(),
+		Reflexive:              rule.Reflexive.ValueBool(),
+		Enabled:                rule.Enabled.ValueBool(),
+		Precedence:             precedence,
+	}
+}
+
+// routingRulesFromDynamic converts a list, tuple or set of rule objects. Rules without a
+// precedence attribute take their position in the list, counting from 1.
+func routingRulesFromDynamic(value types.Dynamic) ([]routingpolicy.Rule, error) {
+	if value.IsNull() || value.IsUnderlyingValueNull() {
+		return nil, fmt.Errorf("rules must not be null")
+	}
+
+	var elements []attr.Value
+	switch collection := value.UnderlyingValue().(type) {
+	case basetypes.ListValue:
+		elements = collection.Elements()
+	case basetypes.TupleValue:
+		elements = collection.Elements()
+	case basetypes.SetValue:
+		elements = collection.Elements()
+	default:
+		return nil, fmt.Errorf("rules must be a list of rule objects, got: %s", value.UnderlyingValue().Type(context.Background()))
+	}
+
+	rules := make([]routingpolicy.Rule, 0, len(elements))
+	for i, element := range elements {
+		object, ok := element.(basetypes.ObjectValue)
+		if !ok || object.IsNull() {
+			return nil, fmt.Errorf("rule %d must be a rule object", i)
+		}
+
+		model, precedence, err := routingRuleModelFromAttributes(object.Attributes())
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %s", i, err)
+		}
+		if precedence == nil {
+			position := int32(i + 1)
+			precedence = &position
+		}
+
+		rules = append(rules, routingPolicyRule(model, *precedence))
+	}
+
+	return rules, nil
+}
+
+// routingRuleModelFromAttributes maps the attributes of a rule object onto RoutingRuleModel,
+// returning the precedence attribute separately as the model has none.
+func routingRuleModelFromAttributes(attributes map[string]attr.Value) (RoutingRuleModel, *int32, error) {
+	rule := RoutingRuleModel{
+		Description:            types.StringNull(),
+		RuleAction:             types.StringNull(),
+		RuleDirection:          types.StringNull(),
+		SourceIpPattern:        types.StringNull(),
+		DestinationIpPattern:   types.StringNull(),
+		DivertIp:               types.StringNull(),
+		DivertPort:             types.StringNull(),
+		TransportProtocol:      types.StringNull(),
+		SourcePortPattern:      types.StringNull(),
+		DestinationPortPattern: types.StringNull(),
+		RoutingTarget:          types.StringNull(),
+		Reflexive:              types.BoolNull(),
+		RegionalGateway:        types.StringNull(),
+		Enabled:                types.BoolNull(),
+	}
+
+	stringAttributes := map[string]*types.String{
+		"description":              &rule.Description,
+		"rule_action":              &rule.RuleAction,
+		"rule_direction":           &rule.RuleDirection,
+		"source_ip_pattern":        &rule.SourceIpPattern,
+		"destination_ip_pattern":   &rule.DestinationIpPattern,
+		"divert_ip":                &rule.DivertIp,
+		"divert_port":              &rule.DivertPort,
+		"transport_protocol":       &rule.TransportProtocol,
+		"source_port_pattern":      &rule.SourcePortPattern,
+		"destination_port_pattern": &rule.DestinationPortPattern,
+		"routing_target":           &rule.RoutingTarget,
+		"regional_gateway":         &rule.RegionalGateway,
+	}
+	boolAttributes := map[string]*types.Bool{
+		"reflexive": &rule.Reflexive,
+		"enabled":   &rule.Enabled,
+	}
+
+	var precedence *int32
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := attributes[name]
+		if value.IsNull() {
+			continue
+		}
+
+		if target, ok := stringAttributes[name]; ok {
+			stringValue, ok := stringOrMoniker(value)
+			if !ok {
+				return rule, nil, fmt.Errorf("%s must be a string", name)
+			}
+			*target = stringValue
+			continue
+		}
+
+		if target, ok := boolAttributes[name]; ok {
+			boolValue, ok := value.(basetypes.BoolValue)
+			if !ok {
+				return rule, nil, fmt.Errorf("%s must be a bool", name)
+			}
+			*target = boolValue
+			continue
+		}
+
+		switch name {
+		case "precedence":
+			number, err := int32Attribute(value)
+			if err != nil {
+				return rule, nil, fmt.Errorf("precedence %s", err)
+			}
+			precedence = &number
+		case "id", "routing_policy_id":
+			// Read by the stacuity_routing_policies data source, but not used to match flows
+		default:
+			return rule, nil, fmt.Errorf("unsupported attribute %q", name)
+		}
+	}
+
+	return rule, precedence, nil
+}
+
+// stringOrMoniker returns a string attribute, or the moniker of a referenced object such as the
+// rule_action read by the stacuity_routing_policies data source.
+func stringOrMoniker(value attr.Value) (types.String, bool) {
+	switch v := value.(type) {
+	case basetypes.StringValue:
+		return v, true
+	case basetypes.ObjectValue:
+		moniker, ok := v.Attributes()["moniker"].(basetypes.StringValue)
+		return moniker, ok
+	}
+
+	return types.StringNull(), false
+}
+
+// int32Attribute returns a whole number attribute, which may arrive as a number or an int.
+func int32Attribute(value attr.Value) (int32, error) {
+	var number *big.Float
+	switch v := value.(type) {
+	case basetypes.NumberValue:
+		number = v.ValueBigFloat()
+	case basetypes.Int64Value:
+		number = new(big.Float).SetInt64(v.ValueInt64())
+	case basetypes.Int32Value:
+		number = new(big.Float).SetInt64(int64(v.ValueInt32()))
+	default:
+		return 0, fmt.Errorf("must be a number")
+	}
+
+	whole, accuracy := number.Int64()
+	if accuracy != big.Exact || whole < -1<<31 || whole > 1<<31-1 {
+		return 0, fmt.Errorf("must be a whole number")
+	}
+
+	return int32(whole), nil
+}
+
+// routingPolicyFlow converts the flow argument.
+func routingPolicyFlow(flow evaluateRoutingPolicyFlowModel) (routingpolicy.Flow, error) {
+	sourceIP, err := netip.ParseAddr(strings.TrimSpace(flow.SourceIp.ValueString()))
+	if err != nil {
+		return routingpolicy.Flow{}, fmt.Errorf("invalid source_ip: %s", err)
+	}
+
+	destinationIP, err := netip.ParseAddr(strings.TrimSpace(flow.DestinationIp.ValueString()))
+	if err != nil {
+		return routingpolicy.Flow{}, fmt.Errorf("invalid destination_ip: %s", err)
+	}
+
+	sourcePort, err := flowPort("source_port", flow.SourcePort)
+	if err != nil {
+		return routingpolicy.Flow{}, err
+	}
+
+	destinationPort, err := flowPort("destination_port", flow.DestinationPort)
+	if err != nil {
+		return routingpolicy.Flow{}, err
+	}
+
+	return routingpolicy.Flow{
+		Direction:       flow.Direction.ValueString(),
+		Protocol:        flow.Protocol.ValueString(),
+		SourceIP:        sourceIP,
+		DestinationIP:   destinationIP,
+		SourcePort:      sourcePort,
+		DestinationPort: destinationPort,
+	}, nil
+}
+
+// flowPort returns a port of the flow, 0 when null.
+func flowPort(name string, port types.Int64) (uint16, error) {
+	if port.IsNull() {
+		return 0, nil
+	}
+
+	if port.ValueInt64() < 1 || port.ValueInt64() > 65535 {
+		return 0, fmt.Errorf("%s must be between 1 and 65535, got: %d", name, port.ValueInt64())
+	}
+
+	return uint16(port.ValueInt64()), nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+const testEvaluateRoutingPolicyRules = `
+locals {
+  rules = [
+    {
+      description              = "web"
+      rule_action              = "forward"
+      rule_direction           = "uplink"
+      transport_protocol       = "tcp"
+      destination_ip_pattern   = "10.0.0.0/24"
+      destination_port_pattern = "443"
+      routing_target           = "internet"
+      reflexive                = true
+      enabled                  = true
+    },
+    {
+      description = "drop all"
+      rule_action = "drop"
+      enabled     = true
+    },
+  ]
+}
+`
+
+func TestEvaluateRoutingPolicyFunction(t *testing.T) {
+	server := newFakeServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeProviderConfig(server) + testEvaluateRoutingPolicyRules + `
+output "web" {
+  value = provider::stacuity::evaluate_routing_policy(local.rules, {
+    direction        = "uplink"
+    protocol         = "tcp"
+    source_ip        = "100.64.0.1"
+    destination_ip   = "10.0.0.1"
+    source_port      = 50000
+    destination_port = 443
+  })
+}
+
+output "return_traffic" {
+  value = provider::stacuity::evaluate_routing_policy(local.rules, {
+    direction        = "downlink"
+    protocol         = "tcp"
+    source_ip        = "10.0.0.1"
+    destination_ip   = "100.64.0.1"
+    source_port      = 443
+    destination_port = 50000
+  })
+}
+
+output "other" {
+  value = provider::stacuity::evaluate_routing_policy(local.rules, {
+    direction        = "uplink"
+    protocol         = "udp"
+    source_ip        = "100.64.0.1"
+    destination_ip   = "10.0.0.1"
+    source_port      = null
+    destination_port = null
+  })
+}
+
+output "precedence" {
+  value = provider::stacuity::evaluate_routing_policy([
+    merge(local.rules[0], { precedence = 2 }),
+    merge(local.rules[1], { precedence = 1 }),
+  ], {
+    direction        = "uplink"
+    protocol         = "tcp"
+    source_ip        = "100.64.0.1"
+    destination_ip   = "10.0.0.1"
+    source_port      = 50000
+    destination_port = 443
+  })
+}
+
+output "none" {
+  value = provider::stacuity::evaluate_routing_policy([local.rules[0]], {
+    direction        = "uplink"
+    protocol         = "udp"
+    source_ip        = "100.64.0.1"
+    destination_ip   = "10.0.0.1"
+    source_port      = null
+    destination_port = null
+  })
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("web", testEvaluateRoutingPolicyMatch(0, 1, "web", "forward", "internet", false)),
+					statecheck.ExpectKnownOutputValue("return_traffic", testEvaluateRoutingPolicyMatch(0, 1, "web", "forward", "internet", true)),
+					statecheck.ExpectKnownOutputValue("other", testEvaluateRoutingPolicyMatch(1, 2, "drop all", "drop", "", false)),
+					statecheck.ExpectKnownOutputValue("precedence", testEvaluateRoutingPolicyMatch(1, 1, "drop all", "drop", "", false)),
+					statecheck.ExpectKnownOutputValue("none", knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"matched":        knownvalue.Bool(false),
+						"index":          knownvalue.Null(),
+						"precedence":     knownvalue.Null(),
+						"description":    knownvalue.Null(),
+						"rule_action":    knownvalue.Null(),
+						"routing_target": knownvalue.Null(),
+						"reflected":      knownvalue.Bool(false),
+					})),
+				},
+			},
+		},
+	})
+}
+
+// testEvaluateRoutingPolicyMatch checks the result of a flow that matched a rule, whose routing
+// target is null when empty.
+func testEvaluateRoutingPolicyMatch(index, precedence int64, description, action, target string, reflected bool) knownvalue.Check {
+	var routingTarget knownvalue.Check = knownvalue.Null()
+	if target != "" {
+		routingTarget = knownvalue.StringExact(target)
+	}
+
+	return knownvalue.ObjectExact(map[string]knownvalue.Check{
+		"matched":        knownvalue.Bool(true),
+		"index":          knownvalue.Int64Exact(index),
+		"precedence":     knownvalue.Int64Exact(precedence),
+		"description":    knownvalue.StringExact(description),
+		"rule_action":    knownvalue.StringExact(action),
+		"routing_target": routingTarget,
+		"reflected":      knownvalue.Bool(reflected),
+	})
+}
+
+func TestEvaluateRoutingPolicyFunctionErrors(t *testing.T) {
+	server := newFakeServer(t)
+
+	flow := `{
+    direction        = "uplink"
+    protocol         = "tcp"
+    source_ip        = "100.64.0.1"
+    destination_ip   = "10.0.0.1"
+    source_port      = 50000
+    destination_port = 443
+  }`
+
+	tests := map[string]struct {
+		rules     string
+		flow      string
+		wantError *regexp.Regexp
+	}{
+		"invalid pattern": {
+			rules:     `[{ rule_action = "drop", destination_ip_pattern = "10.0.0.0/33", enabled = true }]`,
+			flow:      flow,
+			wantError: regexp.MustCompile(`destination_ip_pattern`),
+		},
+		"unsupported attribute": {
+			rules:     `[{ rule_action = "drop", colour = "red" }]`,
+			flow:      flow,
+			wantError: regexp.MustCompile(`unsupported\s+attribute\s+"colour"`),
+		},
+		"not a list": {
+			rules:     `"drop"`,
+			flow:      flow,
+			wantError: regexp.MustCompile(`rules\s+must\s+be\s+a\s+list\s+of\s+rule\s+objects`),
+		},
+		"invalid direction": {
+			rules: `[{ rule_action = "drop", enabled = true }]`,
+			flow: `{
+    direction        = "sideways"
+    protocol         = null
+    source_ip        = "100.64.0.1"
+    destination_ip   = "10.0.0.1"
+    source_port      = null
+    destination_port = null
+  }`,
+			wantError: regexp.MustCompile(`flow\s+direction\s+must\s+be\s+uplink\s+or\s+downlink`),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: testFakeProviderConfig(server) + `
+output "test" {
+  value = provider::stacuity::evaluate_routing_policy(` + test.rules + `, ` + test.flow + `)
+}
+`,
+						ExpectError: test.wantError,
+					},
+				},
+			})
+		})
+	}
+}
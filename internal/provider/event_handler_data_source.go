@@ -60,6 +60,7 @@ func (d *eventHandlerDataSource) Metadata(_ context.Context, req datasource.Meta
 type eventHandlersDataSourceModel struct {
 	EventHandlers []eventHandlerReadModel `tfsdk:"eventhandlers"`
 	Filter        types.Object            `tfsdk:"filter"`
+	All           types.Bool              `tfsdk:"all"`
 }
 
 // eventHandlerReadModel maps schema data.
@@ -125,6 +126,7 @@ func (d *eventHandlerDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 										"bearer_token": schema.StringAttribute{
 											Description: "Bearer token for the webhook",
 											Computed:    true,
+											Sensitive:   true,
 										},
 										"url": schema.StringAttribute{
 											Description: "URL for the webhook",
@@ -137,6 +139,7 @@ func (d *eventHandlerDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 										"password": schema.StringAttribute{
 											Description: "Password for the webhook",
 											Computed:    true,
+											Sensitive:   true,
 										},
 										"timeout": schema.StringAttribute{
 											Description: "Timeout for the webhook",
@@ -166,6 +169,10 @@ func (d *eventHandlerDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -209,7 +216,12 @@ func (d *eventHandlerDataSource) Read(ctx context.Context, req datasource.ReadRe
 		}
 	}
 
-	eventHandlers, err := d.client.GetEventHandlers(pagingQuery)
+	getEventHandlers := d.client.GetEventHandlers
+	if state.All.ValueBool() {
+		getEventHandlers = d.client.GetAllEventHandlers
+	}
+
+	eventHandlers, err := getEventHandlers(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity event handlers",
@@ -7,13 +7,16 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	stacuity "stacuity.com/go_client"
 	models "stacuity.com/go_client/models"
@@ -37,16 +40,43 @@ type eventHandlerResource struct {
 }
 
 type eventHandlerResourceModel struct {
-	Id                types.String      `tfsdk:"id"`
-	Name              types.String      `tfsdk:"name"`
-	Moniker           types.String      `tfsdk:"moniker"`
-	ConfigurationData configurationData `tfsdk:"configuration_data"`
-	EventEndpointType types.String      `tfsdk:"event_endpoint_type"`
+	Id                types.String                  `tfsdk:"id"`
+	Version           types.String                  `tfsdk:"version"`
+	Name              types.String                  `tfsdk:"name"`
+	Moniker           types.String                  `tfsdk:"moniker"`
+	ConfigurationData eventHandlerConfigurationData `tfsdk:"configuration_data"`
+	EventEndpointType types.String                  `tfsdk:"event_endpoint_type"`
 }
 
+type eventHandlerConfigurationData struct {
+	WebhookConfig *eventHandlerWebhookConfig `tfsdk:"webhook_config"`
+}
+
+// eventHandlerWebhookConfig - webhookConfig with the write-only secret attributes, which only the resource has
+type eventHandlerWebhookConfig struct {
+	Url                  types.String `tfsdk:"url"`
+	Username             types.String `tfsdk:"username"`
+	Password             types.String `tfsdk:"password"`
+	PasswordWO           types.String `tfsdk:"password_wo"`
+	PasswordWOVersion    types.Int32  `tfsdk:"password_wo_version"`
+	Timeout              types.String `tfsdk:"timeout"`
+	BearerToken          types.String `tfsdk:"bearer_token"`
+	BearerTokenWO        types.String `tfsdk:"bearer_token_wo"`
+	BearerTokenWOVersion types.Int32  `tfsdk:"bearer_token_wo_version"`
+}
+
+// The webhook config secrets that can also be set write-only
+var (
+	webhookPasswordSecret    = writeOnlySecret{parent: path.Root("configuration_data").AtName("webhook_config"), name: "password"}
+	webhookBearerTokenSecret = writeOnlySecret{parent: path.Root("configuration_data").AtName("webhook_config"), name: "bearer_token"}
+	webhookSecrets           = []writeOnlySecret{webhookPasswordSecret, webhookBearerTokenSecret}
+)
+
 func (r *eventHandlerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "event handler", r.client.GetEventHandler, func(item models.EventHandlerReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *eventHandlerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -66,6 +96,13 @@ func (r *eventHandlerResource) Schema(ctx context.Context, req resource.SchemaRe
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the event handler as last read from the API. Updates are rejected if the event handler has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the Event Handler.",
 				Required:    true,
@@ -91,8 +128,26 @@ func (r *eventHandlerResource) Schema(ctx context.Context, req resource.SchemaRe
 						Required: true,
 						Attributes: map[string]schema.Attribute{
 							"bearer_token": schema.StringAttribute{
-								Description: "Bearer token for the webhook",
+								Description: "Bearer token for the webhook. Stored in state; set bearer_token_wo instead to keep it out of state.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"bearer_token_wo": schema.StringAttribute{
+								Description: "Bearer token for the webhook. Write-only, so it is never stored in state. Requires Terraform 1.11 or later. Change bearer_token_wo_version to send a new value.",
+								Optional:    true,
+								Sensitive:   true,
+								WriteOnly:   true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("bearer_token")),
+									stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("bearer_token_wo_version")),
+								},
+							},
+							"bearer_token_wo_version": schema.Int32Attribute{
+								Description: "Version of bearer_token_wo. Change it to send a new write-only value.",
 								Optional:    true,
+								Validators: []validator.Int32{
+									int32validator.AlsoRequires(path.MatchRelative().AtParent().AtName("bearer_token_wo")),
+								},
 							},
 							"url": schema.StringAttribute{
 								Description: "URL for the webhook",
@@ -103,8 +158,26 @@ func (r *eventHandlerResource) Schema(ctx context.Context, req resource.SchemaRe
 								Optional:    true,
 							},
 							"password": schema.StringAttribute{
-								Description: "Password for the webhook",
+								Description: "Password for the webhook. Stored in state; set password_wo instead to keep it out of state.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"password_wo": schema.StringAttribute{
+								Description: "Password for the webhook. Write-only, so it is never stored in state. Requires Terraform 1.11 or later. Change password_wo_version to send a new value.",
+								Optional:    true,
+								Sensitive:   true,
+								WriteOnly:   true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("password")),
+									stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("password_wo_version")),
+								},
+							},
+							"password_wo_version": schema.Int32Attribute{
+								Description: "Version of password_wo. Change it to send a new write-only value.",
 								Optional:    true,
+								Validators: []validator.Int32{
+									int32validator.AlsoRequires(path.MatchRelative().AtParent().AtName("password_wo")),
+								},
 							},
 							"timeout": schema.StringAttribute{
 								Description: "Timeout for the webhook",
@@ -118,6 +191,21 @@ func (r *eventHandlerResource) Schema(ctx context.Context, req resource.SchemaRe
 	}
 }
 
+// applyWebhookWriteOnlySecrets copies write-only webhook secrets from the configuration into the API request.
+func applyWebhookWriteOnlySecrets(ctx context.Context, config tfsdk.Config, apiData *models.EventHandlerModifyItem, diags *diag.Diagnostics) {
+	webhook := apiData.ConfigurationData.WebhookConfig
+	if webhook == nil {
+		return
+	}
+
+	if password := webhookPasswordSecret.configValue(ctx, config, diags); !password.IsNull() {
+		webhook.Password = password.ValueStringPointer()
+	}
+	if bearerToken := webhookBearerTokenSecret.configValue(ctx, config, diags); !bearerToken.IsNull() {
+		webhook.BearerToken = bearerToken.ValueStringPointer()
+	}
+}
+
 // Configure implements resource.ResourceWithConfigure.
 func (r *eventHandlerResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -162,8 +250,15 @@ func (r *eventHandlerResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	// Create new event handler
-	createResponse, err := r.client.CreateEventHandler(apiData)
+	// Write-only secrets are never in the plan, only in the configuration
+	applyWebhookWriteOnlySecrets(ctx, req.Config, &apiData, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new event handler, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	createResponse, err := r.client.CreateEventHandler(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating event handler",
@@ -173,7 +268,7 @@ func (r *eventHandlerResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	if createResponse.Success {
-		getResponse, err := r.client.GetEventHandler(createResponse.Data)
+		getResponse, err := r.client.GetEventHandler(ctx, createResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading event handler",
@@ -200,6 +295,9 @@ func (r *eventHandlerResource) Create(ctx context.Context, req resource.CreateRe
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	for _, secret := range webhookSecrets {
+		secret.syncState(ctx, req.Plan, &resp.State, &resp.Diagnostics)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -208,26 +306,28 @@ func (r *eventHandlerResource) Create(ctx context.Context, req resource.CreateRe
 // Read refreshes the Terraform state with the latest data.
 // Read resource information.
 func (r *eventHandlerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Get current state
-	var state eventHandlerResourceModel
-	diags := req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	// Get the id and moniker from the current state. After an import they are the only
+	// attributes set, which configuration_data cannot be decoded from.
+	var id, moniker types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(id, moniker)
 	// Get refreshed event handler values
-	apiResponse, err := r.client.GetEventHandler(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetEventHandler(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
 
 		resp.Diagnostics.AddError(
 			"Error Reading event handler Info",
-			"Could not read event handler Moniker "+state.Moniker.ValueString()+": "+err.Error(),
+			"Could not read event handler Moniker "+moniker.ValueString()+": "+err.Error(),
 		)
 		return
 	}
@@ -243,11 +343,14 @@ func (r *eventHandlerResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	configDataModel.EventEndpointType = types.StringValue(apiResponse.EventEndpointType.Moniker)
-	state = configDataModel
+	state := configDataModel
 
 	// Set refreshed state
-	diags = resp.State.Set(ctx, &state)
+	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+	for _, secret := range webhookSecrets {
+		secret.syncState(ctx, req.State, &resp.State, &resp.Diagnostics)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -273,9 +376,25 @@ func (r *eventHandlerResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	// Update existing event handler
-	_, err = r.client.UpdateEventHandler(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Write-only secrets are never in the plan, only in the configuration
+	applyWebhookWriteOnlySecrets(ctx, req.Config, &apiConfigDataModel, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update existing event handler, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+	_, err = r.client.UpdateEventHandler(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "event handler", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating event handler Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update event handler, unexpected error: "+err.Error(),
@@ -283,9 +402,12 @@ func (r *eventHandlerResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
 	// Fetch updated event handler to update state
 	// populated.
-	apiResponse, err := r.client.GetEventHandler(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetEventHandler(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading event handler Info",
@@ -310,6 +432,9 @@ func (r *eventHandlerResource) Update(ctx context.Context, req resource.UpdateRe
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	for _, secret := range webhookSecrets {
+		secret.syncState(ctx, req.Plan, &resp.State, &resp.Diagnostics)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -324,11 +449,17 @@ func (r *eventHandlerResource) Delete(ctx context.Context, req resource.DeleteRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing event handler
-	result, err := r.client.DeleteEventHandler(state.Moniker.ValueString())
+	result, err := r.client.DeleteEventHandler(ctx, key)
 
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting event handler",
 			"Could not delete event handler, unexpected error: "+err.Error(),
@@ -60,6 +60,7 @@ func (d *eventMapDataSource) Metadata(_ context.Context, req datasource.Metadata
 type eventMapsDataSourceModel struct {
 	EventMaps []eventMapReadModel `tfsdk:"eventmaps"`
 	Filter    types.Object        `tfsdk:"filter"`
+	All       types.Bool          `tfsdk:"all"`
 }
 
 // eventMapReadModel maps schema data.
@@ -197,6 +198,10 @@ func (d *eventMapDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -240,7 +245,12 @@ func (d *eventMapDataSource) Read(ctx context.Context, req datasource.ReadReques
 		}
 	}
 
-	eventMaps, err := d.client.GetEventMaps(pagingQuery)
+	getEventMaps := d.client.GetEventMaps
+	if state.All.ValueBool() {
+		getEventMaps = d.client.GetAllEventMaps
+	}
+
+	eventMaps, err := getEventMaps(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity event maps",
@@ -250,7 +260,7 @@ func (d *eventMapDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 	for _, eventMap := range eventMaps {
 
-		subscription, err := d.client.GetEventMapSubscriptions(eventMap.Moniker)
+		subscription, err := d.client.GetEventMapSubscriptions(ctx, eventMap.Moniker)
 
 		if err != nil {
 			resp.Diagnostics.AddError(
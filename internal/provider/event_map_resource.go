@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -38,6 +39,7 @@ type eventMapResource struct {
 
 type eventMapResourceModel struct {
 	Id            types.String            `tfsdk:"id"`
+	Version       types.String            `tfsdk:"version"`
 	Name          types.String            `tfsdk:"name"`
 	Moniker       types.String            `tfsdk:"moniker"`
 	EventScope    types.String            `tfsdk:"event_scope"`
@@ -63,8 +65,10 @@ type eventHandler struct {
 }
 
 func (r *eventMapResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "event map", r.client.GetEventMap, func(item models.EventMapReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *eventMapResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -84,6 +88,13 @@ func (r *eventMapResource) Schema(ctx context.Context, req resource.SchemaReques
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the event map as last read from the API. Updates are rejected if the event map has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the event map.",
 				Required:    true,
@@ -106,7 +117,7 @@ func (r *eventMapResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"subscriptions": schema.SetNestedAttribute{
-				Description: "List of subscriptions attached to event map.",
+				Description: "List of subscriptions attached to event map. When unset, subscriptions are left unmanaged so they can be attached with `stacuity_event_subscription` instead. Removing the attribute once it has been set removes the subscriptions it managed.",
 				Optional:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -169,8 +180,9 @@ func (r *eventMapResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	// Create new event map
-	createResponse, err := r.client.CreateEventMap(apiData)
+	// Create new event map, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	createResponse, err := r.client.CreateEventMap(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating event map",
@@ -181,7 +193,7 @@ func (r *eventMapResource) Create(ctx context.Context, req resource.CreateReques
 
 	if createResponse.Success {
 
-		getResponse, err := r.client.GetEventMap(createResponse.Data)
+		getResponse, err := r.client.GetEventMap(ctx, createResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading event map",
@@ -193,10 +205,13 @@ func (r *eventMapResource) Create(ctx context.Context, req resource.CreateReques
 		configDataModel := eventMapResourceModel{}
 
 		err = stacuity.ConvertFromAPI(getResponse, &configDataModel)
+		if plan.Subscriptions != nil {
+			configDataModel.Subscriptions = &[]subscriptionResource{}
+		}
 
 		// Add subscriptions
 		if apiData.Subscriptions != nil && len(*apiData.Subscriptions) > 0 {
-			createSubResponse, err := r.client.AddEventMapSubscriptions(*apiData.Subscriptions, getResponse.Moniker)
+			createSubResponse, err := r.client.AddEventMapSubscriptions(ctx, *apiData.Subscriptions, getResponse.Moniker)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error creating event map",
@@ -257,12 +272,14 @@ func (r *eventMapResource) Read(ctx context.Context, req resource.ReadRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
+
 	// Get refreshed event map values
-	apiResponse, err := r.client.GetEventMap(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetEventMap(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -274,7 +291,7 @@ func (r *eventMapResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	subscriptionsResponse, err := r.client.GetEventMapSubscriptions(state.Moniker.ValueString())
+	subscriptionsResponse, err := r.client.GetEventMapSubscriptions(ctx, key)
 	if err != nil {
 
 		resp.Diagnostics.AddError(
@@ -284,7 +301,9 @@ func (r *eventMapResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	if len(subscriptionsResponse) > 0 {
+	// Subscriptions are only tracked when managed inline; otherwise they may belong to
+	// stacuity_event_subscription resources.
+	if state.Subscriptions != nil {
 		apiResponse.Subscriptions = &subscriptionsResponse
 	}
 
@@ -341,9 +360,24 @@ func (r *eventMapResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	// Update existing event map
-	_, err = r.client.UpdateEventMap(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Update existing event map, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	var stateSubscriptions *[]subscriptionResource
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("subscriptions"), &stateSubscriptions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	key := objectKey(id, moniker)
+	_, err = r.client.UpdateEventMap(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "event map", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating event map Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update event map, unexpected error: "+err.Error(),
@@ -351,21 +385,36 @@ func (r *eventMapResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	// Add subscriptions
-	if apiConfigDataModel.Subscriptions != nil && len(*apiConfigDataModel.Subscriptions) > 0 {
-		_, err = r.client.AddEventMapSubscriptions(*apiConfigDataModel.Subscriptions, plan.Moniker.ValueString())
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
+	// Reconcile subscriptions, applying only the adds and removals needed. Subscriptions that
+	// have never been set are left alone so they can be managed by stacuity_event_subscription
+	// resources, but removing the attribute from config removes the subscriptions it managed.
+	if plan.Subscriptions != nil || stateSubscriptions != nil {
+		currentSubscriptions, err := r.client.GetEventMapSubscriptions(ctx, key)
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Error adding event map subscriptions Info Moniker:"+plan.Moniker.ValueString(),
-				"Could not update event map subscriptions, unexpected error: "+err.Error(),
+				"Error Reading event map Subscriptions",
+				"Could not read event map subscriptions, unexpected error: "+err.Error(),
 			)
 			return
 		}
+
+		desiredSubscriptions := []models.EventMapSubscriptionModifyItem{}
+		if apiConfigDataModel.Subscriptions != nil {
+			desiredSubscriptions = *apiConfigDataModel.Subscriptions
+		}
+
+		r.reconcileSubscriptions(ctx, key, currentSubscriptions, desiredSubscriptions, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	// Fetch updated event map to update state
 	// populated.
-	apiResponse, err := r.client.GetEventMap(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetEventMap(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading event map Info",
@@ -374,7 +423,7 @@ func (r *eventMapResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	subscriptionsResponse, err := r.client.GetEventMapSubscriptions(plan.Moniker.ValueString())
+	subscriptionsResponse, err := r.client.GetEventMapSubscriptions(ctx, key)
 	if err != nil {
 
 		resp.Diagnostics.AddError(
@@ -384,20 +433,6 @@ func (r *eventMapResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	subscriptionResourceRead := &[]subscriptionResourceRead{}
-	if len(subscriptionsResponse) > 0 {
-		err = stacuity.ConvertFromAPI(subscriptionsResponse, subscriptionResourceRead)
-
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error converting to TF",
-				"Could not convert event map, unexpected error: "+err.Error(),
-			)
-			return
-		}
-
-	}
-
 	// Update resource state with updated items
 	configDataModel := eventMapResourceModel{}
 	err = stacuity.ConvertFromAPI(apiResponse, &configDataModel)
@@ -410,12 +445,12 @@ func (r *eventMapResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	//reset
-	if len(*subscriptionResourceRead) > 0 {
+	if plan.Subscriptions != nil {
 		configDataModel.Subscriptions = &[]subscriptionResource{}
-		for _, sub := range *subscriptionResourceRead {
+		for _, sub := range subscriptionsResponse {
 			newSub := subscriptionResource{}
-			newSub.EventEndpointId = types.StringValue(sub.EventHandler.Moniker.ValueString())
-			newSub.EventTypeId = types.StringValue(sub.EventType.Moniker.ValueString())
+			newSub.EventEndpointId = types.StringValue(sub.EventEndpoint.Moniker)
+			newSub.EventTypeId = types.StringValue(sub.EventType.Moniker)
 			*configDataModel.Subscriptions = append(*configDataModel.Subscriptions, newSub)
 		}
 	}
@@ -430,6 +465,43 @@ func (r *eventMapResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 }
 
+// reconcileSubscriptions updates the subscriptions of the event map with the given id or moniker, matched by handler and event type, to the desired ones.
+func (r *eventMapResource) reconcileSubscriptions(ctx context.Context, key string, current []models.Subscription, desired []models.EventMapSubscriptionModifyItem, diags *diag.Diagnostics) {
+	diff := diffEntries(current, desired, entryKeys[models.Subscription, models.EventMapSubscriptionModifyItem]{
+		current: func(sub models.Subscription) string {
+			return subscriptionKey(sub.EventEndpoint.Moniker, sub.EventType.Moniker)
+		},
+		desired: func(sub models.EventMapSubscriptionModifyItem) string {
+			return subscriptionKey(sub.EventEndpointId, sub.EventTypeId)
+		},
+		id: func(sub models.Subscription) string {
+			return sub.Id
+		},
+	})
+
+	for _, subscriptionId := range diff.Remove {
+		_, err := r.client.DeleteEventMapSubscription(ctx, key, subscriptionId)
+		if err != nil && !stacuity.IsNotFound(err) {
+			diags.AddError(
+				"Error removing event map subscription Info:"+key,
+				"Could not remove event map subscription "+subscriptionId+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if len(diff.Add) > 0 {
+		_, err := r.client.AddEventMapSubscriptions(ctx, diff.Add, key)
+		if err != nil {
+			diags.AddError(
+				"Error adding event map subscriptions Info:"+key,
+				"Could not update event map subscriptions, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *eventMapResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Retrieve values from state
@@ -439,11 +511,17 @@ func (r *eventMapResource) Delete(ctx context.Context, req resource.DeleteReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing event map
-	result, err := r.client.DeleteEventMap(state.Moniker.ValueString())
+	result, err := r.client.DeleteEventMap(ctx, key)
 
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting event map",
 			"Could not delete event map, unexpected error: "+err.Error(),
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"stacuity.com/go_client/fake"
+	"stacuity.com/go_client/models"
+)
+
+func TestEventMapResourceSubscriptions(t *testing.T) {
+	server := newFakeServer(t)
+
+	handler := models.EventHandlerModifyItem{Name: "Webhook", Moniker: "tf-webhook", EventEndpointType: "webhook"}
+	if _, err := server.APIClient().CreateEventHandler(context.Background(), handler); err != nil {
+		t.Fatal(err)
+	}
+
+	online := `{ event_endpoint_id = "tf-webhook", event_type_id = "endpoint-online" }`
+	offline := `{ event_endpoint_id = "tf-webhook", event_type_id = "endpoint-offline" }`
+
+	// addSubscription attaches a subscription outside of Terraform.
+	addSubscription := func() {
+		_, err := server.APIClient().AddEventMapSubscriptions(context.Background(), []models.EventMapSubscriptionModifyItem{
+			{EventEndpointId: "tf-webhook", EventTypeId: "sim-created"},
+		}, "tf-event-map")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeProviderConfig(server) + testEventMapConfig(online, offline),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("stacuity_event_map.test", "subscriptions.#", "2"),
+					testCheckEventMapSubscriptions(server, "endpoint-offline", "endpoint-online"),
+				),
+			},
+			// Managed subscriptions report drift
+			{
+				PreConfig: addSubscription,
+				Config:    testFakeProviderConfig(server) + testEventMapConfig(online, offline),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("stacuity_event_map.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testCheckEventMapSubscriptions(server, "endpoint-offline", "endpoint-online"),
+			},
+			// Removing the attribute removes the subscriptions it managed
+			{
+				Config: testFakeProviderConfig(server) + testEventMapConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("stacuity_event_map.test", "subscriptions"),
+					testCheckEventMapSubscriptions(server),
+				),
+			},
+			// Unmanaged subscriptions are left alone
+			{
+				PreConfig: addSubscription,
+				Config:    testFakeProviderConfig(server) + testEventMapConfig(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("stacuity_event_map.test", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: testCheckEventMapSubscriptions(server, "sim-created"),
+			},
+		},
+	})
+}
+
+// testEventMapConfig returns an event map with the given subscriptions, or without the
+// attribute when there are none.
+func testEventMapConfig(subscriptions ...string) string {
+	attribute := ""
+	if len(subscriptions) > 0 {
+		attribute = fmt.Sprintf("subscriptions = [%s]", strings.Join(subscriptions, ", "))
+	}
+
+	return fmt.Sprintf(`
+resource "stacuity_event_map" "test" {
+  name        = "Terraform event map"
+  moniker     = "tf-event-map"
+  event_scope = "vslice"
+  %s
+}
+`, attribute)
+}
+
+// testCheckEventMapSubscriptions checks the event types subscribed to on the fake API.
+func testCheckEventMapSubscriptions(server *fake.Server, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		subscriptions, err := server.APIClient().GetEventMapSubscriptions(context.Background(), "tf-event-map")
+		if err != nil {
+			return err
+		}
+
+		got := []string{}
+		for _, sub := range subscriptions {
+			got = append(got, sub.EventType.Moniker)
+		}
+
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("event map has subscriptions %v, want %v", got, want)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	stacuity "stacuity.com/go_client"
+	models "stacuity.com/go_client/models"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &eventSubscriptionResource{}
+	_ resource.ResourceWithConfigure   = &eventSubscriptionResource{}
+	_ resource.ResourceWithImportState = &eventSubscriptionResource{}
+)
+
+// NewEventSubscriptionResource is a helper function to simplify the provider implementation.
+func NewEventSubscriptionResource() resource.Resource {
+	return &eventSubscriptionResource{}
+}
+
+// eventSubscriptionResource is the resource implementation.
+type eventSubscriptionResource struct {
+	client *stacuity.Client
+}
+
+type eventSubscriptionResourceModel struct {
+	Id           types.String `tfsdk:"id"`
+	EventMap     types.String `tfsdk:"event_map"`
+	EventHandler types.String `tfsdk:"event_handler"`
+	EventType    types.String `tfsdk:"event_type"`
+}
+
+// ImportState accepts an id of the form <event_map>/<event_handler>/<event_type>.
+func (r *eventSubscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import identifier",
+			"Expected an import identifier of the form <event_map>/<event_handler>/<event_type>, got: "+req.ID,
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("event_map"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("event_handler"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("event_type"), parts[2])...)
+}
+
+func (r *eventSubscriptionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_event_subscription"
+}
+
+func (r *eventSubscriptionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "event subscription resource. Attaches a single event handler to an event map, so subscriptions on a shared event map can be owned by separate configurations. Do not combine with the `subscriptions` attribute of `stacuity_event_map` on the same event map.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The identifier for the event subscription.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"event_map": schema.StringAttribute{
+				Description: "API Moniker of the event map.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"event_handler": schema.StringAttribute{
+				Description: "Id or API Moniker of the event handler.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"event_type": schema.StringAttribute{
+				Description: "Key or API Moniker of the type of event to subscribe to.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// Configure implements resource.ResourceWithConfigure.
+func (r *eventSubscriptionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*stacuity.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *stacuity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *eventSubscriptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan eventSubscriptionResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	eventMap := plan.EventMap.ValueString()
+
+	// Refuse to take over a subscription that already exists, as it may belong to another configuration
+	current, err := r.client.GetEventMapSubscriptions(ctx, eventMap)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading event map Subscriptions",
+			"Could not read event map subscriptions, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if existing := findSubscription(current, plan); existing != nil {
+		resp.Diagnostics.AddError(
+			"Error creating event subscription",
+			"Event map "+eventMap+" is already subscribed to "+plan.EventType.ValueString()+" events with handler "+plan.EventHandler.ValueString()+". "+
+				"Import the subscription to manage it with this resource.",
+		)
+		return
+	}
+
+	subscription := models.EventMapSubscriptionModifyItem{
+		EventEndpointId: plan.EventHandler.ValueString(),
+		EventTypeId:     plan.EventType.ValueString(),
+	}
+
+	createResponse, err := r.client.AddEventMapSubscriptions(ctx, []models.EventMapSubscriptionModifyItem{subscription}, eventMap)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating event subscription",
+			"Could not create event subscription, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if len(createResponse.Data) != 1 {
+		resp.Diagnostics.AddError(
+			"Error creating event subscription",
+			fmt.Sprintf("Expected the API to return 1 subscription, got: %d", len(createResponse.Data)),
+		)
+		return
+	}
+
+	plan.Id = types.StringValue(createResponse.Data[0].EventSubscriptionId)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *eventSubscriptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state eventSubscriptionResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.GetEventMapSubscriptions(ctx, state.EventMap.ValueString())
+	if err != nil {
+		if stacuity.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Error Reading event map Subscriptions",
+			"Could not read event map subscriptions, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	subscription := findSubscription(current, state)
+	if subscription == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Id = types.StringValue(subscription.Id)
+
+	// Keep the configured handler and event type when they name the same objects by id, so
+	// an id in the configuration does not force a replacement on every plan.
+	if !eventHandlerMatches(state.EventHandler.ValueString(), subscription.EventEndpoint) {
+		state.EventHandler = types.StringValue(subscription.EventEndpoint.Moniker)
+	}
+	if !eventTypeMatches(state.EventType.ValueString(), subscription.EventType) {
+		state.EventType = types.StringValue(subscription.EventType.Moniker)
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update is never called with changes, as every attribute requires replacement.
+func (r *eventSubscriptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan eventSubscriptionResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *eventSubscriptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state eventSubscriptionResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.DeleteEventMapSubscription(ctx, state.EventMap.ValueString(), state.Id.ValueString())
+	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Error Deleting event subscription",
+			"Could not delete event subscription, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// findSubscription returns the subscription with the id in model, or when the id is not yet
+// known, as after an import, the one with its handler and event type.
+func findSubscription(subscriptions []models.Subscription, model eventSubscriptionResourceModel) *models.Subscription {
+	for i, subscription := range subscriptions {
+		if model.Id.ValueString() != "" {
+			if subscription.Id == model.Id.ValueString() {
+				return &subscriptions[i]
+			}
+			continue
+		}
+
+		if eventHandlerMatches(model.EventHandler.ValueString(), subscription.EventEndpoint) &&
+			eventTypeMatches(model.EventType.ValueString(), subscription.EventType) {
+			return &subscriptions[i]
+		}
+	}
+
+	return nil
+}
+
+// eventHandlerMatches reports whether value names the event handler by id or moniker.
+func eventHandlerMatches(value string, handler models.EventEndpoint) bool {
+	return value == handler.Moniker || handler.Id != "" && value == handler.Id
+}
+
+// eventTypeMatches reports whether value names the event type by key or moniker.
+func eventTypeMatches(value string, eventType models.EventType) bool {
+	return value == eventType.Moniker || eventType.Key != 0 && value == strconv.Itoa(int(eventType.Key))
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"stacuity.com/go_client/models"
+)
+
+func TestEventSubscriptionResourceHandlerReference(t *testing.T) {
+	tests := map[string]struct {
+		byID bool
+	}{
+		"moniker": {},
+		"id":      {byID: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeServer(t)
+			client := server.APIClient()
+
+			created, err := client.CreateEventHandler(context.Background(), models.EventHandlerModifyItem{Name: "Webhook", Moniker: "tf-webhook", EventEndpointType: "webhook"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.CreateEventMap(context.Background(), models.EventMapModifyItem{Name: "Terraform event map", Moniker: "tf-event-map", EventScope: "vslice"}); err != nil {
+				t.Fatal(err)
+			}
+
+			handler := "tf-webhook"
+			if test.byID {
+				handler = created.Data
+			}
+			config := testFakeProviderConfig(server) + testEventSubscriptionConfig(handler)
+
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: config,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("stacuity_event_subscription.test", "event_handler", handler),
+							resource.TestCheckResourceAttr("stacuity_event_subscription.test", "event_type", "endpoint-online"),
+							testCheckEventMapSubscriptions(server, "endpoint-online"),
+						),
+					},
+					// The configured reference is kept rather than replaced by the moniker
+					{
+						Config: config,
+						ConfigPlanChecks: resource.ConfigPlanChecks{
+							PreApply: []plancheck.PlanCheck{
+								plancheck.ExpectResourceAction("stacuity_event_subscription.test", plancheck.ResourceActionNoop),
+							},
+						},
+					},
+				},
+			})
+		})
+	}
+}
+
+func testEventSubscriptionConfig(handler string) string {
+	return fmt.Sprintf(`
+resource "stacuity_event_subscription" "test" {
+  event_map     = "tf-event-map"
+  event_handler = %q
+  event_type    = "endpoint-online"
+}
+`, handler)
+}
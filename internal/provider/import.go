@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	stacuity "stacuity.com/go_client"
+)
+
+const (
+	importMonikerPrefix = "moniker:"
+	importIdPrefix      = "id:"
+)
+
+// importObject resolves an import identifier through the API and stores the id and moniker
+// of the object it names, leaving Read to fill in the rest. The identifier is either an id or
+// a moniker, optionally prefixed with "id:" or "moniker:" to say which.
+func importObject[T any](ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse, objectType string, get func(context.Context, string) (T, error), keys func(T) (id, moniker string)) {
+	key := strings.TrimSpace(req.ID)
+	switch {
+	case strings.HasPrefix(key, importMonikerPrefix):
+		key = strings.TrimPrefix(key, importMonikerPrefix)
+	case strings.HasPrefix(key, importIdPrefix):
+		key = strings.TrimPrefix(key, importIdPrefix)
+	}
+
+	if key == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import identifier",
+			"Expected the id or moniker of the "+objectType+", such as moniker:example, got: "+req.ID,
+		)
+		return
+	}
+
+	object, err := get(ctx, key)
+	if err != nil {
+		if stacuity.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Cannot import non-existent remote object",
+				"No "+objectType+" with id or moniker "+key+" exists.",
+			)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Error Importing "+objectType,
+			"Could not read "+objectType+" "+key+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	id, moniker := keys(object)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("moniker"), moniker)...)
+}
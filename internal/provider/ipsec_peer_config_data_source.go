@@ -0,0 +1,391 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	stacuity "stacuity.com/go_client"
+	models "stacuity.com/go_client/models"
+)
+
+// ipsecPresharedKeyPlaceholder is rendered when the API does not return the pre-shared key, or
+// returns one swanctl.conf cannot hold.
+const ipsecPresharedKeyPlaceholder = "<pre-shared key>"
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ipsecPeerConfigDataSource{}
+	_ datasource.DataSourceWithConfigure = &ipsecPeerConfigDataSource{}
+)
+
+// NewIPsecPeerConfigDataSource is a helper function to simplify the provider implementation.
+func NewIPsecPeerConfigDataSource() datasource.DataSource {
+	return &ipsecPeerConfigDataSource{}
+}
+
+// ipsecPeerConfigDataSource is the data source implementation.
+type ipsecPeerConfigDataSource struct {
+	client *stacuity.Client
+}
+
+// ipsecPeerConfigDataSourceModel maps the data source schema data.
+type ipsecPeerConfigDataSourceModel struct {
+	RoutingTarget   types.String `tfsdk:"routing_target"`
+	StacuityAddress types.String `tfsdk:"stacuity_address"`
+	PeerAddress     types.String `tfsdk:"peer_address"`
+	Id              types.String `tfsdk:"id"`
+	PresharedKey    types.String `tfsdk:"preshared_key"`
+	Summary         types.Map    `tfsdk:"summary"`
+	SwanctlConf     types.String `tfsdk:"swanctl_conf"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *ipsecPeerConfigDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*stacuity.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *stacuity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Metadata returns the data source type name.
+func (d *ipsecPeerConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ipsec_peer_config"
+}
+
+// Schema defines the schema for the data source.
+func (d *ipsecPeerConfigDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders the configuration of the customer-side peer of a VPN (IPsec) routing target, as a strongSwan `swanctl.conf` and as a generic key/value summary for other firewalls.",
+		Attributes: map[string]schema.Attribute{
+			"routing_target": schema.StringAttribute{
+				Description: "Id or moniker of the VPN routing target.",
+				Required:    true,
+			},
+			"stacuity_address": schema.StringAttribute{
+				Description: "Address of the Stacuity VPN gateway. Defaults to the public instance configuration of the routing target when that holds a host name or address.",
+				Optional:    true,
+			},
+			"peer_address": schema.StringAttribute{
+				Description: "Address of the customer-side peer. Defaults to the remote peer address of the routing target; set it when the peer sits behind NAT.",
+				Optional:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The identifier for the routing target.",
+				Computed:    true,
+			},
+			"preshared_key": schema.StringAttribute{
+				Description: "The pre-shared key of the tunnel.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"summary": schema.MapAttribute{
+				Description: "The tunnel settings as seen from the customer-side peer, keyed by setting name, with lifetimes in seconds. The pre-shared key is left out, see preshared_key.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"swanctl_conf": schema.StringAttribute{
+				Description: "The rendered strongSwan swanctl.conf, including the pre-shared key unless it holds control characters.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ipsecPeerConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ipsecPeerConfigDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	routingTarget, err := d.client.GetRoutingTarget(ctx, state.RoutingTarget.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Stacuity Routing Target",
+			err.Error(),
+		)
+		return
+	}
+
+	if routingTarget.ConfigurationData == nil || routingTarget.ConfigurationData.VpnConfig == nil {
+		resp.Diagnostics.AddError(
+			"Not a VPN routing target",
+			"Routing target "+routingTarget.Moniker+" has no VPN configuration.",
+		)
+		return
+	}
+
+	vpn := routingTarget.ConfigurationData.VpnConfig
+	if vpn.PresharedKey == "" {
+		resp.Diagnostics.AddWarning(
+			"VPN pre-shared key not returned",
+			"Routing target "+routingTarget.Moniker+" did not return a pre-shared key, so a placeholder is rendered in its place.",
+		)
+	} else if _, ok := swanctlQuote(vpn.PresharedKey); !ok {
+		resp.Diagnostics.AddWarning(
+			"VPN pre-shared key not rendered",
+			"The pre-shared key of routing target "+routingTarget.Moniker+" holds control characters, which swanctl.conf cannot hold, so a placeholder is rendered in its place. The key is still available in preshared_key.",
+		)
+	}
+
+	stacuityAddress := state.StacuityAddress.ValueString()
+	if stacuityAddress == "" && isEndpoint(routingTarget.PublicInstanceConfiguration) {
+		stacuityAddress = routingTarget.PublicInstanceConfiguration
+	}
+	if stacuityAddress == "" {
+		resp.Diagnostics.AddWarning(
+			"Stacuity VPN gateway address unknown",
+			"Routing target "+routingTarget.Moniker+" does not report a public address, so the configuration accepts any remote address. Set stacuity_address to restrict it.",
+		)
+	}
+
+	peerAddress := state.PeerAddress.ValueString()
+	if peerAddress == "" {
+		peerAddress = vpn.RemotePeerAddress
+	}
+
+	peer := newIPsecPeer(routingTarget.Moniker, vpn, stacuityAddress, peerAddress)
+
+	summary, diags := types.MapValueFrom(ctx, types.StringType, peer.summary())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Id = types.StringValue(routingTarget.Id)
+	state.PresharedKey = types.StringValue(vpn.PresharedKey)
+	state.Summary = summary
+	state.SwanctlConf = types.StringValue(peer.swanctlConf())
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ipsecPeer - The settings of a VPN routing target as seen from the customer-side peer, where
+// Stacuity's remote settings are local and its local settings are remote
+type ipsecPeer struct {
+	Name              string
+	IKEVersion        int
+	LocalAddress      string
+	RemoteAddress     string
+	LocalTS           []string
+	RemoteTS          []string
+	IKEProposal       string
+	ESPProposal       string
+	IKELifetime       int32
+	ChildLifetime     int32
+	PresharedKey      string
+	LocalSubnets      string
+	RemoteSubnets     string
+	LocalEncDomain    string
+	RemoteEncDomain   string
+	KeyExchangeType   string
+	RoutingTargetName string
+}
+
+// newIPsecPeer maps the VPN configuration of a routing target onto the customer-side peer.
+// The encryption domains are used as traffic selectors, falling back to the subnets.
+func newIPsecPeer(moniker string, vpn *models.VpnConfig, stacuityAddress string, peerAddress string) ipsecPeer {
+	localTS := splitList(vpn.RemoteEncryptionDomain)
+	if len(localTS) == 0 {
+		localTS = splitList(vpn.RemoteSubnets)
+	}
+
+	remoteTS := splitList(vpn.LocalEncryptionDomain)
+	if len(remoteTS) == 0 {
+		remoteTS = splitList(vpn.LocalSubnets)
+	}
+
+	return ipsecPeer{
+		Name:              swanctlName(moniker),
+		IKEVersion:        ikeVersion(vpn.KeyExchangeType),
+		LocalAddress:      peerAddress,
+		RemoteAddress:     stacuityAddress,
+		LocalTS:           localTS,
+		RemoteTS:          remoteTS,
+		IKEProposal:       vpn.VpnIkeOption,
+		ESPProposal:       vpn.VpnEspOption,
+		IKELifetime:       vpn.Phase1Lifetime,
+		ChildLifetime:     vpn.Phase2Lifetime,
+		PresharedKey:      vpn.PresharedKey,
+		LocalSubnets:      vpn.RemoteSubnets,
+		RemoteSubnets:     vpn.LocalSubnets,
+		LocalEncDomain:    vpn.RemoteEncryptionDomain,
+		RemoteEncDomain:   vpn.LocalEncryptionDomain,
+		KeyExchangeType:   vpn.KeyExchangeType,
+		RoutingTargetName: moniker,
+	}
+}
+
+// summary returns the settings by name, leaving out the pre-shared key and anything unset.
+func (p ipsecPeer) summary() map[string]string {
+	summary := map[string]string{
+		"authentication": "psk",
+	}
+
+	set := func(key string, value string) {
+		if value != "" {
+			summary[key] = value
+		}
+	}
+	setLifetime := func(key string, value int32) {
+		if value > 0 {
+			summary[key] = strconv.Itoa(int(value))
+		}
+	}
+
+	set("key_exchange_type", p.KeyExchangeType)
+	set("peer_address", p.LocalAddress)
+	set("stacuity_address", p.RemoteAddress)
+	set("peer_subnets", p.LocalSubnets)
+	set("stacuity_subnets", p.RemoteSubnets)
+	set("peer_encryption_domain", p.LocalEncDomain)
+	set("stacuity_encryption_domain", p.RemoteEncDomain)
+	set("ike_proposal", p.IKEProposal)
+	set("esp_proposal", p.ESPProposal)
+	setLifetime("phase1_lifetime", p.IKELifetime)
+	setLifetime("phase2_lifetime", p.ChildLifetime)
+
+	return summary
+}
+
+// swanctlConf returns the configuration in strongSwan swanctl.conf format, leaving out unset
+// optional settings. The API does not state the unit of the phase 1 and phase 2 lifetimes; they
+// are taken to be seconds, as IKE lifetimes conventionally are.
+func (p ipsecPeer) swanctlConf() string {
+	var b strings.Builder
+	line := func(indent int, format string, args ...any) {
+		b.WriteString(strings.Repeat("    ", indent))
+		fmt.Fprintf(&b, format, args...)
+		b.WriteString("\n")
+	}
+
+	remoteAddress := p.RemoteAddress
+	if remoteAddress == "" {
+		remoteAddress = "%any"
+	}
+
+	secret, ok := swanctlQuote(p.PresharedKey)
+	if p.PresharedKey == "" || !ok {
+		secret, _ = swanctlQuote(ipsecPresharedKeyPlaceholder)
+	}
+
+	line(0, "# Stacuity routing target %s", p.RoutingTargetName)
+	line(0, "connections {")
+	line(1, "%s {", p.Name)
+	if p.IKEVersion > 0 {
+		line(2, "version = %d", p.IKEVersion)
+	}
+	if p.LocalAddress != "" {
+		line(2, "local_addrs = %s", p.LocalAddress)
+	}
+	line(2, "remote_addrs = %s", remoteAddress)
+	if p.IKEProposal != "" {
+		line(2, "proposals = %s", p.IKEProposal)
+	}
+	if p.IKELifetime > 0 {
+		line(2, "rekey_time = %ds", p.IKELifetime)
+	}
+	line(2, "local {")
+	line(3, "auth = psk")
+	line(2, "}")
+	line(2, "remote {")
+	line(3, "auth = psk")
+	line(2, "}")
+	line(2, "children {")
+	line(3, "%s {", p.Name)
+	if len(p.LocalTS) > 0 {
+		line(4, "local_ts = %s", strings.Join(p.LocalTS, ", "))
+	}
+	if len(p.RemoteTS) > 0 {
+		line(4, "remote_ts = %s", strings.Join(p.RemoteTS, ", "))
+	}
+	if p.ESPProposal != "" {
+		line(4, "esp_proposals = %s", p.ESPProposal)
+	}
+	if p.ChildLifetime > 0 {
+		line(4, "rekey_time = %ds", p.ChildLifetime)
+	}
+	line(4, "start_action = start")
+	line(3, "}")
+	line(2, "}")
+	line(1, "}")
+	line(0, "}")
+	line(0, "")
+	line(0, "secrets {")
+	line(1, "ike-%s {", p.Name)
+	if p.LocalAddress != "" {
+		line(2, "id-1 = %s", p.LocalAddress)
+	}
+	if p.RemoteAddress != "" {
+		line(2, "id-2 = %s", p.RemoteAddress)
+	}
+	line(2, "secret = %s", secret)
+	line(1, "}")
+	line(0, "}")
+
+	return b.String()
+}
+
+// ikeVersion returns the IKE version named by a key exchange type such as ikev2, or 0 for any.
+func ikeVersion(keyExchangeType string) int {
+	switch strings.ToLower(strings.TrimSpace(keyExchangeType)) {
+	case "ikev1", "ike1", "1":
+		return 1
+	case "ikev2", "ike2", "2":
+		return 2
+	}
+
+	return 0
+}
+
+// swanctlQuote returns s as a quoted swanctl.conf string. strongSwan only understands escaped
+// backslashes and double quotes, so ok is false when s holds control characters, which cannot be
+// written into a quoted value. Other characters, including non-ASCII ones, are written as is.
+func swanctlQuote(s string) (quoted string, ok bool) {
+	if strings.ContainsFunc(s, unicode.IsControl) {
+		return "", false
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`, true
+}
+
+// swanctlName returns moniker with the characters swanctl does not allow in section names replaced.
+func swanctlName(moniker string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, moniker)
+
+	if name == "" {
+		return "stacuity"
+	}
+
+	return name
+}
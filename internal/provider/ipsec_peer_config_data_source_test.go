@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	models "stacuity.com/go_client/models"
+)
+
+// testVpnConfig returns the VPN configuration of a routing target as the API reports it, from
+// the Stacuity side.
+func testVpnConfig() *models.VpnConfig {
+	return &models.VpnConfig{
+		LocalSubnets:           "10.100.0.0/16",
+		RemoteSubnets:          "192.168.0.0/24, 192.168.1.0/24",
+		RemotePeerAddress:      "198.51.100.7",
+		LocalEncryptionDomain:  "10.100.1.0/24",
+		RemoteEncryptionDomain: "192.168.0.10/32",
+		PresharedKey:           "s3cret",
+		KeyExchangeType:        "ikev2",
+		VpnIkeOption:           "aes256-sha256-modp2048",
+		VpnEspOption:           "aes256-sha256",
+		Phase1Lifetime:         28800,
+		Phase2Lifetime:         3600,
+	}
+}
+
+func TestNewIPsecPeer(t *testing.T) {
+	tests := map[string]struct {
+		vpn          func(vpn *models.VpnConfig)
+		wantLocalTS  []string
+		wantRemoteTS []string
+	}{
+		"encryption domains": {
+			wantLocalTS:  []string{"192.168.0.10/32"},
+			wantRemoteTS: []string{"10.100.1.0/24"},
+		},
+		"subnets without encryption domains": {
+			vpn: func(vpn *models.VpnConfig) {
+				vpn.LocalEncryptionDomain, vpn.RemoteEncryptionDomain = "", ""
+			},
+			wantLocalTS:  []string{"192.168.0.0/24", "192.168.1.0/24"},
+			wantRemoteTS: []string{"10.100.0.0/16"},
+		},
+		"one side without an encryption domain": {
+			vpn: func(vpn *models.VpnConfig) {
+				vpn.LocalEncryptionDomain = ""
+			},
+			wantLocalTS:  []string{"192.168.0.10/32"},
+			wantRemoteTS: []string{"10.100.0.0/16"},
+		},
+		"neither": {
+			vpn: func(vpn *models.VpnConfig) {
+				*vpn = models.VpnConfig{}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			vpn := testVpnConfig()
+			if test.vpn != nil {
+				test.vpn(vpn)
+			}
+
+			peer := newIPsecPeer("vpn.test", vpn, "203.0.113.1", "198.51.100.7")
+
+			if !slices.Equal(peer.LocalTS, test.wantLocalTS) {
+				t.Errorf("got local_ts %q, want %q", peer.LocalTS, test.wantLocalTS)
+			}
+			if !slices.Equal(peer.RemoteTS, test.wantRemoteTS) {
+				t.Errorf("got remote_ts %q, want %q", peer.RemoteTS, test.wantRemoteTS)
+			}
+		})
+	}
+}
+
+func TestNewIPsecPeerSwapsSides(t *testing.T) {
+	vpn := testVpnConfig()
+	peer := newIPsecPeer("vpn.test", vpn, "203.0.113.1", "198.51.100.7")
+
+	// Stacuity's remote side is the peer's local side, and the other way around.
+	want := ipsecPeer{
+		Name:              "vpn-test",
+		IKEVersion:        2,
+		LocalAddress:      "198.51.100.7",
+		RemoteAddress:     "203.0.113.1",
+		LocalTS:           []string{"192.168.0.10/32"},
+		RemoteTS:          []string{"10.100.1.0/24"},
+		IKEProposal:       vpn.VpnIkeOption,
+		ESPProposal:       vpn.VpnEspOption,
+		IKELifetime:       28800,
+		ChildLifetime:     3600,
+		PresharedKey:      "s3cret",
+		LocalSubnets:      vpn.RemoteSubnets,
+		RemoteSubnets:     vpn.LocalSubnets,
+		LocalEncDomain:    vpn.RemoteEncryptionDomain,
+		RemoteEncDomain:   vpn.LocalEncryptionDomain,
+		KeyExchangeType:   "ikev2",
+		RoutingTargetName: "vpn.test",
+	}
+
+	if !reflect.DeepEqual(peer, want) {
+		t.Errorf("got %+v, want %+v", peer, want)
+	}
+}
+
+func TestIPsecPeerSwanctlConf(t *testing.T) {
+	tests := map[string]struct {
+		vpn             *models.VpnConfig
+		stacuityAddress string
+		peerAddress     string
+		want            string
+	}{
+		"full configuration": {
+			vpn:             testVpnConfig(),
+			stacuityAddress: "203.0.113.1",
+			peerAddress:     "198.51.100.7",
+			want: `# Stacuity routing target vpn.test
+connections {
+    vpn-test {
+        version = 2
+        local_addrs = 198.51.100.7
+        remote_addrs = 203.0.113.1
+        proposals = aes256-sha256-modp2048
+        rekey_time = 28800s
+        local {
+            auth = psk
+        }
+        remote {
+            auth = psk
+        }
+        children {
+            vpn-test {
+                local_ts = 192.168.0.10/32
+                remote_ts = 10.100.1.0/24
+                esp_proposals = aes256-sha256
+                rekey_time = 3600s
+                start_action = start
+            }
+        }
+    }
+}
+
+secrets {
+    ike-vpn-test {
+        id-1 = 198.51.100.7
+        id-2 = 203.0.113.1
+        secret = "s3cret"
+    }
+}
+`,
+		},
+		"unknown addresses and key": {
+			vpn: &models.VpnConfig{LocalSubnets: "10.100.0.0/16", RemoteSubnets: "192.168.0.0/24"},
+			want: `# Stacuity routing target vpn.test
+connections {
+    vpn-test {
+        remote_addrs = %any
+        local {
+            auth = psk
+        }
+        remote {
+            auth = psk
+        }
+        children {
+            vpn-test {
+                local_ts = 192.168.0.0/24
+                remote_ts = 10.100.0.0/16
+                start_action = start
+            }
+        }
+    }
+}
+
+secrets {
+    ike-vpn-test {
+        secret = "<pre-shared key>"
+    }
+}
+`,
+		},
+		"only the peer address": {
+			vpn:         &models.VpnConfig{PresharedKey: `with "quotes"`},
+			peerAddress: "198.51.100.7",
+			want: `# Stacuity routing target vpn.test
+connections {
+    vpn-test {
+        local_addrs = 198.51.100.7
+        remote_addrs = %any
+        local {
+            auth = psk
+        }
+        remote {
+            auth = psk
+        }
+        children {
+            vpn-test {
+                start_action = start
+            }
+        }
+    }
+}
+
+secrets {
+    ike-vpn-test {
+        id-1 = 198.51.100.7
+        secret = "with \"quotes\""
+    }
+}
+`,
+		},
+		"key with control characters": {
+			vpn: &models.VpnConfig{PresharedKey: "line\nbreak"},
+			want: `# Stacuity routing target vpn.test
+connections {
+    vpn-test {
+        remote_addrs = %any
+        local {
+            auth = psk
+        }
+        remote {
+            auth = psk
+        }
+        children {
+            vpn-test {
+                start_action = start
+            }
+        }
+    }
+}
+
+secrets {
+    ike-vpn-test {
+        secret = "<pre-shared key>"
+    }
+}
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := newIPsecPeer("vpn.test", test.vpn, test.stacuityAddress, test.peerAddress).swanctlConf()
+			if got != test.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIPsecPeerSummary(t *testing.T) {
+	got := newIPsecPeer("vpn.test", testVpnConfig(), "203.0.113.1", "198.51.100.7").summary()
+	want := map[string]string{
+		"authentication":             "psk",
+		"key_exchange_type":          "ikev2",
+		"peer_address":               "198.51.100.7",
+		"stacuity_address":           "203.0.113.1",
+		"peer_subnets":               "192.168.0.0/24, 192.168.1.0/24",
+		"stacuity_subnets":           "10.100.0.0/16",
+		"peer_encryption_domain":     "192.168.0.10/32",
+		"stacuity_encryption_domain": "10.100.1.0/24",
+		"ike_proposal":               "aes256-sha256-modp2048",
+		"esp_proposal":               "aes256-sha256",
+		"phase1_lifetime":            "28800",
+		"phase2_lifetime":            "3600",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got = newIPsecPeer("vpn.test", &models.VpnConfig{PresharedKey: "s3cret"}, "", "").summary()
+	want = map[string]string{"authentication": "psk"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v for an empty configuration, want %v", got, want)
+	}
+}
+
+func TestIKEVersion(t *testing.T) {
+	tests := map[string]int{
+		"ikev1":   1,
+		"IKEv2":   2,
+		" ike2 ":  2,
+		"1":       1,
+		"":        0,
+		"ikev2+1": 0,
+	}
+
+	for keyExchangeType, want := range tests {
+		if got := ikeVersion(keyExchangeType); got != want {
+			t.Errorf("ikeVersion(%q) = %d, want %d", keyExchangeType, got, want)
+		}
+	}
+}
+
+func TestSwanctlQuote(t *testing.T) {
+	tests := map[string]struct {
+		want   string
+		wantOK bool
+	}{
+		"s3cret":          {want: `"s3cret"`, wantOK: true},
+		`with "quotes"`:   {want: `"with \"quotes\""`, wantOK: true},
+		`back\slash`:      {want: `"back\\slash"`, wantOK: true},
+		"ünïcode €":       {want: `"ünïcode €"`, wantOK: true},
+		`\x41 and \u00e9`: {want: `"\\x41 and \\u00e9"`, wantOK: true},
+		"line\nbreak":     {},
+		"tab\there":       {},
+		"nul\x00":         {},
+	}
+
+	for s, test := range tests {
+		got, ok := swanctlQuote(s)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("swanctlQuote(%q) = %q, %t, want %q, %t", s, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestSwanctlName(t *testing.T) {
+	tests := map[string]string{
+		"vpn-test_1": "vpn-test_1",
+		"vpn.test":   "vpn-test",
+		"vpn test/1": "vpn-test-1",
+		"":           "stacuity",
+	}
+
+	for moniker, want := range tests {
+		if got := swanctlName(moniker); got != want {
+			t.Errorf("swanctlName(%q) = %q, want %q", moniker, got, want)
+		}
+	}
+}
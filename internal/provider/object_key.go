@@ -0,0 +1,18 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// objectKey returns the key used to address an object in API calls. Objects are addressed by
+// their id, which stays the same when the moniker is renamed; state written before the id was
+// recorded falls back to the moniker.
+func objectKey(id, moniker types.String) string {
+	if !id.IsNull() && !id.IsUnknown() && id.ValueString() != "" {
+		return id.ValueString()
+	}
+
+	return moniker.ValueString()
+}
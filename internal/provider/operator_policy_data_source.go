@@ -60,6 +60,7 @@ func (d *operatorPolicyDataSource) Metadata(_ context.Context, req datasource.Me
 type OperatorPolicysDataSourceModel struct {
 	OperatorPolicys []OperatorPolicyReadModel `tfsdk:"operatorpolicies"`
 	Filter          types.Object              `tfsdk:"filter"`
+	All             types.Bool                `tfsdk:"all"`
 }
 
 // OperatorPolicyReadModel maps schema data.
@@ -160,6 +161,10 @@ func (d *operatorPolicyDataSource) Schema(_ context.Context, _ datasource.Schema
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -203,7 +208,12 @@ func (d *operatorPolicyDataSource) Read(ctx context.Context, req datasource.Read
 		}
 	}
 
-	operatorPolicies, err := d.client.GetOperatorPolicies(pagingQuery)
+	getOperatorPolicies := d.client.GetOperatorPolicies
+	if state.All.ValueBool() {
+		getOperatorPolicies = d.client.GetAllOperatorPolicies
+	}
+
+	operatorPolicies, err := getOperatorPolicies(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity operator policies",
@@ -213,7 +223,7 @@ func (d *operatorPolicyDataSource) Read(ctx context.Context, req datasource.Read
 	}
 	for _, OperatorPolicy := range operatorPolicies {
 
-		subscription, err := d.client.GetOperatorPolicyEntries(OperatorPolicy.Moniker)
+		subscription, err := d.client.GetOperatorPolicyEntries(ctx, OperatorPolicy.Moniker)
 
 		if err != nil {
 			resp.Diagnostics.AddError(
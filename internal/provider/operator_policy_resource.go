@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -38,6 +39,7 @@ type operatorPolicyResource struct {
 
 type operatorPolicyResourceModel struct {
 	Id      types.String                   `tfsdk:"id"`
+	Version types.String                   `tfsdk:"version"`
 	Moniker types.String                   `tfsdk:"moniker"`
 	Name    types.String                   `tfsdk:"name"`
 	Entries *[]operatorPolicyEntryResource `tfsdk:"entries"`
@@ -50,8 +52,10 @@ type operatorPolicyEntryResource struct {
 }
 
 func (r *operatorPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "operator policy", r.client.GetOperatorPolicy, func(item models.OperatorPolicyReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *operatorPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -71,6 +75,13 @@ func (r *operatorPolicyResource) Schema(ctx context.Context, req resource.Schema
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the operator policy as last read from the API. Updates are rejected if the operator policy has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the Operator Policy.",
 				Required:    true,
@@ -154,8 +165,9 @@ func (r *operatorPolicyResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	// Create new operator policy
-	createResponse, err := r.client.CreateOperatorPolicy(apiData)
+	// Create new operator policy, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	createResponse, err := r.client.CreateOperatorPolicy(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating operator policy",
@@ -166,7 +178,7 @@ func (r *operatorPolicyResource) Create(ctx context.Context, req resource.Create
 
 	if createResponse.Success {
 
-		getResponse, err := r.client.GetOperatorPolicy(createResponse.Data)
+		getResponse, err := r.client.GetOperatorPolicy(ctx, createResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading operator policy",
@@ -179,7 +191,7 @@ func (r *operatorPolicyResource) Create(ctx context.Context, req resource.Create
 
 		// Add Entries
 		if apiData.Entries != nil && len(*apiData.Entries) > 0 {
-			_, err := r.client.AddOperatorPolicyEntries(*apiData.Entries, getResponse.Moniker)
+			_, err := r.client.AddOperatorPolicyEntries(ctx, *apiData.Entries, getResponse.Moniker)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error creating operator policy",
@@ -188,7 +200,17 @@ func (r *operatorPolicyResource) Create(ctx context.Context, req resource.Create
 				return
 			}
 
-			getEntriesResponse, err := r.client.GetOperatorPolicyEntries(getResponse.Moniker)
+			// Read the operator policy again so the version recorded is the one after the entries were added
+			getResponse, err = r.client.GetOperatorPolicy(ctx, createResponse.Data)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error re-reading operator policy",
+					"Could not read operator policy, unexpected error: "+err.Error(),
+				)
+				return
+			}
+
+			getEntriesResponse, err := r.client.GetOperatorPolicyEntries(ctx, getResponse.Moniker)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error getting operator policy",
@@ -245,12 +267,14 @@ func (r *operatorPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
+
 	// Get refreshed operator policy values
-	apiResponse, err := r.client.GetOperatorPolicy(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetOperatorPolicy(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -262,7 +286,7 @@ func (r *operatorPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	entryResponse, err := r.client.GetOperatorPolicyEntries(state.Moniker.ValueString())
+	entryResponse, err := r.client.GetOperatorPolicyEntries(ctx, key)
 	if err != nil {
 
 		resp.Diagnostics.AddError(
@@ -329,9 +353,19 @@ func (r *operatorPolicyResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	// Update existing operator policy
-	_, err = r.client.UpdateOperatorPolicy(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Update existing operator policy, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+	_, err = r.client.UpdateOperatorPolicy(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "operator policy", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating operator policy Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update operator policy, unexpected error: "+err.Error(),
@@ -339,21 +373,32 @@ func (r *operatorPolicyResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	// Add entries
-	if apiConfigDataModel.Entries != nil && len(*apiConfigDataModel.Entries) > 0 {
-		_, err = r.client.AddOperatorPolicyEntries(*apiConfigDataModel.Entries, plan.Moniker.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error adding operator policy entries Info Moniker:"+plan.Moniker.ValueString(),
-				"Could not update operator policy entries, unexpected error: "+err.Error(),
-			)
-			return
-		}
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
+	// Reconcile entries, applying only the adds, changes and removals needed
+	currentEntries, err := r.client.GetOperatorPolicyEntries(ctx, key)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading operator policy Entries",
+			"Could not read operator policy entries, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	desiredEntries := []models.OperatorPolicyEntryModifyItem{}
+	if apiConfigDataModel.Entries != nil {
+		desiredEntries = *apiConfigDataModel.Entries
+	}
+
+	r.reconcileEntries(ctx, key, currentEntries, desiredEntries, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Fetch updated operator policy to update state
 	// populated.
-	apiResponse, err := r.client.GetOperatorPolicy(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetOperatorPolicy(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading operator policy Info",
@@ -362,7 +407,7 @@ func (r *operatorPolicyResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	entriesResponse, err := r.client.GetOperatorPolicyEntries(plan.Moniker.ValueString())
+	entriesResponse, err := r.client.GetOperatorPolicyEntries(ctx, key)
 	if err != nil {
 
 		resp.Diagnostics.AddError(
@@ -408,6 +453,57 @@ func (r *operatorPolicyResource) Update(ctx context.Context, req resource.Update
 	}
 }
 
+// reconcileEntries updates the entries of the policy with the given id or moniker, matched by iso3 and operator id, to the desired ones.
+func (r *operatorPolicyResource) reconcileEntries(ctx context.Context, key string, current []models.OperatorPolicyEntry, desired []models.OperatorPolicyEntryModifyItem, diags *diag.Diagnostics) {
+	diff := diffEntries(current, desired, entryKeys[models.OperatorPolicyEntry, models.OperatorPolicyEntryModifyItem]{
+		current: func(entry models.OperatorPolicyEntry) string {
+			return countryOperatorKey(entry.Iso3, entry.OperatorId)
+		},
+		desired: func(entry models.OperatorPolicyEntryModifyItem) string {
+			return countryOperatorKey(entry.Iso3, entry.OperatorId)
+		},
+		id: func(entry models.OperatorPolicyEntry) string {
+			return entry.Id
+		},
+		same: func(existing models.OperatorPolicyEntry, entry models.OperatorPolicyEntryModifyItem) bool {
+			return existing.SteeringProfileEntryAction.Moniker == entry.SteeringProfileEntryAction
+		},
+	})
+
+	for _, entryId := range diff.Remove {
+		_, err := r.client.DeleteOperatorPolicyEntry(ctx, key, entryId)
+		if err != nil && !stacuity.IsNotFound(err) {
+			diags.AddError(
+				"Error removing operator policy entry Info:"+key,
+				"Could not remove operator policy entry "+entryId+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	for _, change := range diff.Change {
+		_, err := r.client.UpdateOperatorPolicyEntry(ctx, key, change.Id, change.Entry)
+		if err != nil {
+			diags.AddError(
+				"Error updating operator policy entry Info:"+key,
+				"Could not update operator policy entry "+change.Id+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if len(diff.Add) > 0 {
+		_, err := r.client.AddOperatorPolicyEntries(ctx, diff.Add, key)
+		if err != nil {
+			diags.AddError(
+				"Error adding operator policy entries Info:"+key,
+				"Could not update operator policy entries, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *operatorPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Retrieve values from state
@@ -417,11 +513,17 @@ func (r *operatorPolicyResource) Delete(ctx context.Context, req resource.Delete
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing operator policy
-	result, err := r.client.DeleteOperatorPolicy(state.Moniker.ValueString())
+	result, err := r.client.DeleteOperatorPolicy(ctx, key)
 
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting operator policy",
 			"Could not delete operator policy, unexpected error: "+err.Error(),
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"stacuity.com/go_client/fake"
+)
+
+func TestOperatorPolicyResourceEntries(t *testing.T) {
+	server := newFakeServer(t)
+
+	allowGBR := `{ iso_3 = "GBR", steering_profile_entry_action = "allow" }`
+	denyGBR := `{ iso_3 = "GBR", steering_profile_entry_action = "reject-soft" }`
+	allowOperator := `{ iso_3 = "DZA", operator_id = 2145, steering_profile_entry_action = "allow" }`
+	rejectDefault := `{ steering_profile_entry_action = "reject-hard" }`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeProviderConfig(server) + testOperatorPolicyConfig(rejectDefault, allowGBR),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("stacuity_operator_policy.test", "entries.#", "2"),
+					testCheckOperatorPolicyEntries(server, "*/*=reject-hard", "GBR/*=allow"),
+					testCheckOperatorPolicyVersion(server),
+				),
+			},
+			// Add only
+			{
+				Config: testFakeProviderConfig(server) + testOperatorPolicyConfig(rejectDefault, allowGBR, allowOperator),
+				Check:  testCheckOperatorPolicyEntries(server, "*/*=reject-hard", "GBR/*=allow", "DZA/2145=allow"),
+			},
+			// Changed entry
+			{
+				Config: testFakeProviderConfig(server) + testOperatorPolicyConfig(rejectDefault, denyGBR, allowOperator),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("stacuity_operator_policy.test", tfjsonpath.New("version")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckOperatorPolicyEntries(server, "*/*=reject-hard", "GBR/*=reject-soft", "DZA/2145=allow"),
+					testCheckOperatorPolicyVersion(server),
+				),
+			},
+			// Remove only
+			{
+				Config: testFakeProviderConfig(server) + testOperatorPolicyConfig(denyGBR),
+				Check:  testCheckOperatorPolicyEntries(server, "GBR/*=reject-soft"),
+			},
+			// No-op
+			{
+				Config: testFakeProviderConfig(server) + testOperatorPolicyConfig(denyGBR),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("stacuity_operator_policy.test", plancheck.ResourceActionNoop),
+						plancheck.ExpectKnownValue("stacuity_operator_policy.test", tfjsonpath.New("version"), knownvalue.NotNull()),
+					},
+				},
+				Check: testCheckOperatorPolicyEntries(server, "GBR/*=reject-soft"),
+			},
+		},
+	})
+}
+
+func testOperatorPolicyConfig(entries ...string) string {
+	return fmt.Sprintf(`
+resource "stacuity_operator_policy" "test" {
+  name    = "Terraform operator policy"
+  moniker = "tf-operator-policy"
+  entries = [%s]
+}
+`, strings.Join(entries, ", "))
+}
+
+// testCheckOperatorPolicyEntries checks the entries held by the fake API, each given as
+// country/operator=action.
+func testCheckOperatorPolicyEntries(server *fake.Server, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		entries, err := server.APIClient().GetOperatorPolicyEntries(context.Background(), "tf-operator-policy")
+		if err != nil {
+			return err
+		}
+
+		got := []string{}
+		for _, entry := range entries {
+			got = append(got, countryOperatorKey(entry.Iso3, entry.OperatorId)+"="+entry.SteeringProfileEntryAction.Moniker)
+		}
+
+		slices.Sort(got)
+		want = slices.Sorted(slices.Values(want))
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("operator policy has entries %v, want %v", got, want)
+		}
+
+		return nil
+	}
+}
+
+// testCheckOperatorPolicyVersion checks that the version in state is the one held by the fake API.
+func testCheckOperatorPolicyVersion(server *fake.Server) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		policy, err := server.APIClient().GetOperatorPolicy(context.Background(), "tf-operator-policy")
+		if err != nil {
+			return err
+		}
+
+		return resource.TestCheckResourceAttr("stacuity_operator_policy.test", "version", policy.Version)(state)
+	}
+}
@@ -4,24 +4,38 @@ package provider
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
 	stacuity "stacuity.com/go_client"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure StacuityProvider satisfies various provider interfaces.
-var _ provider.Provider = &StacuityProvider{}
+var (
+	_ provider.Provider                       = &StacuityProvider{}
+	_ provider.ProviderWithEphemeralResources = &StacuityProvider{}
+	_ provider.ProviderWithFunctions          = &StacuityProvider{}
+)
 
 // StacuityProvider defines the provider implementation.
 type StacuityProvider struct {
 	version string
+	// cassette, when set, records or replays the API traffic of every client the provider
+	// configures. Only acceptance tests set it.
+	cassette *stacuity.CassetteFile
 }
 
 // StacuityProviderModel describes the provider data model.
@@ -43,17 +57,95 @@ func (p *StacuityProvider) Schema(_ context.Context, _ provider.SchemaRequest, r
 				Optional:    true,
 			},
 			"token": schema.StringAttribute{
-				Description: "Token for Stacuity API. May also be provided via STACUITY_TOKEN environment variable.",
-				Required:    true,
+				Description: "Token for Stacuity API. May also be provided via STACUITY_TOKEN environment variable. Conflicts with client_id and client_secret.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "OAuth2 client ID used to obtain short-lived access tokens with the client credentials flow. May also be provided via STACUITY_CLIENT_ID environment variable.",
+				Optional:    true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description: "OAuth2 client secret used with client_id. May also be provided via STACUITY_CLIENT_SECRET environment variable.",
+				Optional:    true,
 				Sensitive:   true,
 			},
+			"token_url": schema.StringAttribute{
+				Description: "OAuth2 token endpoint used with client_id and client_secret. May also be provided via STACUITY_TOKEN_URL environment variable.",
+				Optional:    true,
+			},
+			"scopes": schema.ListAttribute{
+				Description: "OAuth2 scopes requested with client_id and client_secret.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"max_retry_attempts": schema.Int32Attribute{
+				Description: "Maximum number of attempts for an API request that fails with a transient error (HTTP 429, 5xx or a network error), including the first attempt. " +
+					"Only idempotent requests (GET, PUT, DELETE) are retried. Set to 1 to disable retries. Defaults to 4. May also be provided via STACUITY_MAX_RETRY_ATTEMPTS environment variable.",
+				Optional: true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Description: "Maximum number of API requests per second made by the provider, shared across all parallel operations. Unlimited when not set.",
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.1),
+				},
+			},
+			"request_timeout": schema.StringAttribute{
+				Description: "Time allowed for a single API request, as a duration such as \"60s\" or \"2m\". Defaults to 30s.",
+				Optional:    true,
+			},
+			"https_proxy": schema.StringAttribute{
+				Description: "URL of the proxy used to reach the Stacuity API. When not set the HTTPS_PROXY and NO_PROXY environment variables are honoured.",
+				Optional:    true,
+			},
+			"ca_certificate": schema.StringAttribute{
+				Description: "PEM encoded CA certificate bundle trusted in addition to the system roots, e.g. file(\"ca.pem\").",
+				Optional:    true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip verification of the API server certificate. Only intended for lab environments.",
+				Optional:    true,
+			},
+			"client_certificate": schema.StringAttribute{
+				Description: "PEM encoded client certificate for mutual TLS. Requires client_key.",
+				Optional:    true,
+			},
+			"client_key": schema.StringAttribute{
+				Description: "PEM encoded private key for client_certificate.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"max_concurrency": schema.Int32Attribute{
+				Description: "Maximum number of API requests in flight at once, shared across all parallel operations. Unlimited when not set.",
+				Optional:    true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
 		},
 	}
 }
 
 type stacuityProviderModel struct {
-	Host  types.String `tfsdk:"host"`
-	Token types.String `tfsdk:"token"`
+	Host                 types.String   `tfsdk:"host"`
+	Token                types.String   `tfsdk:"token"`
+	ClientId             types.String   `tfsdk:"client_id"`
+	ClientSecret         types.String   `tfsdk:"client_secret"`
+	TokenURL             types.String   `tfsdk:"token_url"`
+	Scopes               []types.String `tfsdk:"scopes"`
+	MaxRetryAttempts     types.Int32    `tfsdk:"max_retry_attempts"`
+	MaxRequestsPerSecond types.Float64  `tfsdk:"max_requests_per_second"`
+	MaxConcurrency       types.Int32    `tfsdk:"max_concurrency"`
+	RequestTimeout       types.String   `tfsdk:"request_timeout"`
+	HTTPSProxy           types.String   `tfsdk:"https_proxy"`
+	CACertificate        types.String   `tfsdk:"ca_certificate"`
+	InsecureSkipVerify   types.Bool     `tfsdk:"insecure_skip_verify"`
+	ClientCertificate    types.String   `tfsdk:"client_certificate"`
+	ClientKey            types.String   `tfsdk:"client_key"`
 }
 
 func (p *StacuityProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -75,6 +167,9 @@ func (p *StacuityProvider) Configure(ctx context.Context, req provider.Configure
 	// with Terraform configuration value if set.
 	host := os.Getenv("STACUITY_HOST")
 	token := os.Getenv("STACUITY_TOKEN")
+	clientId := os.Getenv("STACUITY_CLIENT_ID")
+	clientSecret := os.Getenv("STACUITY_CLIENT_SECRET")
+	tokenURL := os.Getenv("STACUITY_TOKEN_URL")
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
@@ -88,6 +183,66 @@ func (p *StacuityProvider) Configure(ctx context.Context, req provider.Configure
 		token = config.Token.ValueString()
 	}
 
+	if !config.ClientId.IsNull() {
+		clientId = config.ClientId.ValueString()
+	}
+
+	if !config.ClientSecret.IsNull() {
+		clientSecret = config.ClientSecret.ValueString()
+	}
+
+	if !config.TokenURL.IsNull() {
+		tokenURL = config.TokenURL.ValueString()
+	}
+
+	useClientCredentials := clientId != "" || clientSecret != ""
+
+	maxRetryAttempts := stacuity.DefaultMaxAttempts
+	if value := os.Getenv("STACUITY_MAX_RETRY_ATTEMPTS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retry_attempts"),
+				"Invalid Stacuity Retry Configuration",
+				"The STACUITY_MAX_RETRY_ATTEMPTS environment variable must be a whole number of at least 1, got: "+value,
+			)
+		} else {
+			maxRetryAttempts = parsed
+		}
+	}
+
+	if !config.MaxRetryAttempts.IsNull() {
+		maxRetryAttempts = int(config.MaxRetryAttempts.ValueInt32())
+	}
+
+	transportConfig := stacuity.TransportConfig{
+		ProxyURL:             config.HTTPSProxy.ValueString(),
+		CACertificatePEM:     []byte(config.CACertificate.ValueString()),
+		InsecureSkipVerify:   config.InsecureSkipVerify.ValueBool(),
+		ClientCertificatePEM: []byte(config.ClientCertificate.ValueString()),
+		ClientKeyPEM:         []byte(config.ClientKey.ValueString()),
+	}
+
+	if !config.RequestTimeout.IsNull() {
+		timeout, err := time.ParseDuration(config.RequestTimeout.ValueString())
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Stacuity Request Timeout",
+				"The request_timeout must be a positive duration such as \"60s\" or \"2m\", got: "+config.RequestTimeout.ValueString(),
+			)
+		}
+		transportConfig.Timeout = timeout
+	}
+
+	if config.ClientCertificate.IsNull() != config.ClientKey.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_certificate"),
+			"Incomplete Stacuity Mutual TLS Configuration",
+			"Both client_certificate and client_key must be set to use mutual TLS.",
+		)
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -101,12 +256,42 @@ func (p *StacuityProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
-	if token == "" {
+	if useClientCredentials {
+		if !config.Token.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token"),
+				"Conflicting Stacuity API Credentials",
+				"The provider cannot use both a static token and OAuth2 client credentials. "+
+					"Remove either the token or the client_id and client_secret from the configuration.",
+			)
+		}
+
+		for _, required := range []struct {
+			attribute string
+			envVar    string
+			value     string
+		}{
+			{"client_id", "STACUITY_CLIENT_ID", clientId},
+			{"client_secret", "STACUITY_CLIENT_SECRET", clientSecret},
+			{"token_url", "STACUITY_TOKEN_URL", tokenURL},
+		} {
+			if required.value == "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(required.attribute),
+					"Missing Stacuity OAuth2 Client Credentials",
+					"The provider cannot create the Stacuity API client as there is a missing or empty value for "+required.attribute+". "+
+						"Client credentials authentication needs client_id, client_secret and token_url. "+
+						"Set the "+required.attribute+" value in the configuration or use the "+required.envVar+" environment variable.",
+				)
+			}
+		}
+	} else if token == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
 			"Missing Stacuity API Token",
 			"The provider cannot create the Stacuity API client as there is a missing or empty value for the Stacuity API token. "+
-				"Set the token value in the configuration or use the STACUITY_TOKEN environment variable. "+
+				"Set the token value in the configuration or use the STACUITY_TOKEN environment variable, "+
+				"or configure client_id, client_secret and token_url instead. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -118,7 +303,22 @@ func (p *StacuityProvider) Configure(ctx context.Context, req provider.Configure
 	tflog.Debug(ctx, "Creating Stacuity client")
 
 	// Create a new Stacuity client using the configuration values
-	client, err := stacuity.NewClient(&host, &token)
+	var client *stacuity.Client
+	var err error
+	if useClientCredentials {
+		credentials := stacuity.ClientCredentials{
+			ClientID:     clientId,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+		}
+		for _, scope := range config.Scopes {
+			credentials.Scopes = append(credentials.Scopes, scope.ValueString())
+		}
+
+		client, err = stacuity.NewClientWithCredentials(&host, credentials)
+	} else {
+		client, err = stacuity.NewClient(&host, &token)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Stacuity API Client",
@@ -129,6 +329,26 @@ func (p *StacuityProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	client.HTTPClient, err = stacuity.NewHTTPClient(transportConfig)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Configure Stacuity API Transport",
+			"The provider could not build the HTTP transport from the TLS and proxy settings.\n\n"+
+				"Stacuity Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	if p.cassette != nil {
+		client.UseCassette(p.cassette)
+	}
+
+	client.Retry.MaxAttempts = maxRetryAttempts
+
+	if !config.MaxRequestsPerSecond.IsNull() || !config.MaxConcurrency.IsNull() {
+		client.Throttle = stacuity.NewThrottle(config.MaxRequestsPerSecond.ValueFloat64(), int(config.MaxConcurrency.ValueInt32()))
+	}
+
 	resp.DataSourceData = client
 	resp.ResourceData = client
 
@@ -139,6 +359,7 @@ func (p *StacuityProvider) Resources(ctx context.Context) []func() resource.Reso
 	return []func() resource.Resource{
 		NewVSliceResource, NewRoutingTargetResource, NewRoutingPolicyResource, NewEndpointGroupResource,
 		NewEventMapResource, NewEventHandlerResource, NewOperatorPolicyResource, NewRegionalPolicyResource,
+		NewEventSubscriptionResource,
 	}
 }
 
@@ -174,11 +395,34 @@ func RegionalPolicyDataSource() datasource.DataSource {
 	return &regionalPolicyDataSource{}
 }
 
+func WireGuardPeerConfigDataSource() datasource.DataSource {
+	return &wireGuardPeerConfigDataSource{}
+}
+
+func IPsecPeerConfigDataSource() datasource.DataSource {
+	return &ipsecPeerConfigDataSource{}
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *StacuityProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		VSliceDataSource, RoutingTargetDataSource, RoutingPolicyDataSource, EndpointGroupDataSource, EventMapDataSource,
-		EventHandlerDataSource, OperatorPolicyDataSource, RegionalPolicyDataSource,
+		EventHandlerDataSource, OperatorPolicyDataSource, RegionalPolicyDataSource, WireGuardPeerConfigDataSource,
+		IPsecPeerConfigDataSource,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *StacuityProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewWireGuardKeyPairEphemeralResource,
+	}
+}
+
+// Functions defines the provider-defined functions implemented in the provider.
+func (p *StacuityProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewEvaluateRoutingPolicyFunction,
 	}
 }
 
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	stacuity "stacuity.com/go_client"
+	"stacuity.com/go_client/fake"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance and unit testing. The factory function will be invoked for every
+// Terraform CLI command executed to create a provider server to which the CLI
+// can reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"stacuity": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// newFakeServer starts a fake Stacuity API for the duration of the test.
+func newFakeServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// testFakeProviderConfig returns a provider block pointing at server.
+func testFakeProviderConfig(server *fake.Server) string {
+	return fmt.Sprintf(`
+provider "stacuity" {
+  host  = %q
+  token = %q
+}
+`, server.HostURL(), fake.Token)
+}
+
+// testAccProviderFactories returns provider factories for an acceptance test against the API
+// named by STACUITY_HOST, with the credentials from the environment. When STACUITY_CASSETTE_DIR
+// is set, the test's API traffic is recorded to, or replayed from, a cassette in that directory
+// named after the test, as STACUITY_CASSETTE_MODE says. Replay needs no credentials, so the
+// provider is given a placeholder token instead.
+func testAccProviderFactories(t *testing.T) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Helper()
+
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	dir := os.Getenv("STACUITY_CASSETTE_DIR")
+	if dir == "" {
+		testAccPreCheck(t)
+		return testAccProtoV6ProviderFactories
+	}
+
+	mode := stacuity.CassetteMode(os.Getenv("STACUITY_CASSETTE_MODE"))
+	if mode == "" {
+		mode = stacuity.CassetteReplay
+	}
+
+	path := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+	cassette, err := stacuity.OpenCassette(path, mode)
+	if err != nil {
+		t.Fatalf("opening cassette: %s", err)
+	}
+
+	if mode == stacuity.CassetteReplay {
+		t.Setenv("STACUITY_TOKEN", "replay")
+		t.Setenv("STACUITY_CLIENT_ID", "")
+		t.Setenv("STACUITY_CLIENT_SECRET", "")
+	} else {
+		testAccPreCheck(t)
+	}
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"stacuity": providerserver.NewProtocol6WithError(&StacuityProvider{version: "test", cassette: cassette}),
+	}
+}
+
+// testAccPreCheck fails an acceptance test that would send requests to the API without
+// credentials.
+func testAccPreCheck(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("STACUITY_TOKEN") == "" && os.Getenv("STACUITY_CLIENT_ID") == "" {
+		t.Fatal("STACUITY_TOKEN or STACUITY_CLIENT_ID must be set for acceptance tests")
+	}
+}
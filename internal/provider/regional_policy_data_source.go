@@ -60,6 +60,7 @@ func (d *regionalPolicyDataSource) Metadata(_ context.Context, req datasource.Me
 type RegionalPolicysDataSourceModel struct {
 	RegionalPolicys []RegionalPolicyReadModel `tfsdk:"regionalpolicies"`
 	Filter          types.Object              `tfsdk:"filter"`
+	All             types.Bool                `tfsdk:"all"`
 }
 
 // RegionalPolicyReadModel maps schema data.
@@ -155,6 +156,10 @@ func (d *regionalPolicyDataSource) Schema(_ context.Context, _ datasource.Schema
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -198,7 +203,12 @@ func (d *regionalPolicyDataSource) Read(ctx context.Context, req datasource.Read
 		}
 	}
 
-	regionalPolicies, err := d.client.GetRegionalPolicies(pagingQuery)
+	getRegionalPolicies := d.client.GetRegionalPolicies
+	if state.All.ValueBool() {
+		getRegionalPolicies = d.client.GetAllRegionalPolicies
+	}
+
+	regionalPolicies, err := getRegionalPolicies(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity regional policies",
@@ -208,7 +218,7 @@ func (d *regionalPolicyDataSource) Read(ctx context.Context, req datasource.Read
 	}
 	for _, RegionalPolicy := range regionalPolicies {
 
-		subscription, err := d.client.GetRegionalPolicyEntries(RegionalPolicy.Moniker)
+		subscription, err := d.client.GetRegionalPolicyEntries(ctx, RegionalPolicy.Moniker)
 
 		if err != nil {
 			resp.Diagnostics.AddError(
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -38,6 +39,7 @@ type regionalPolicyResource struct {
 
 type regionalPolicyResourceModel struct {
 	Id      types.String                   `tfsdk:"id"`
+	Version types.String                   `tfsdk:"version"`
 	Moniker types.String                   `tfsdk:"moniker"`
 	Name    types.String                   `tfsdk:"name"`
 	Entries *[]regionalPolicyEntryResource `tfsdk:"entries"`
@@ -50,8 +52,10 @@ type regionalPolicyEntryResource struct {
 }
 
 func (r *regionalPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "regional policy", r.client.GetRegionalPolicy, func(item models.RegionalPolicyReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *regionalPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -103,6 +107,13 @@ func (r *regionalPolicyResource) Schema(ctx context.Context, req resource.Schema
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the regional policy as last read from the API. Updates are rejected if the regional policy has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the Regional Policy.",
 				Required:    true,
@@ -186,8 +197,9 @@ func (r *regionalPolicyResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	// Create new regional policy
-	createResponse, err := r.client.CreateRegionalPolicy(apiData)
+	// Create new regional policy, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	createResponse, err := r.client.CreateRegionalPolicy(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating regional policy",
@@ -198,7 +210,7 @@ func (r *regionalPolicyResource) Create(ctx context.Context, req resource.Create
 
 	if createResponse.Success {
 
-		getResponse, err := r.client.GetRegionalPolicy(createResponse.Data)
+		getResponse, err := r.client.GetRegionalPolicy(ctx, createResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading regional policy",
@@ -211,7 +223,7 @@ func (r *regionalPolicyResource) Create(ctx context.Context, req resource.Create
 
 		// Add Entries
 		if apiData.Entries != nil && len(*apiData.Entries) > 0 {
-			_, err := r.client.AddRegionalPolicyEntries(*apiData.Entries, getResponse.Moniker)
+			_, err := r.client.AddRegionalPolicyEntries(ctx, *apiData.Entries, getResponse.Moniker)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error creating regional policy",
@@ -220,7 +232,17 @@ func (r *regionalPolicyResource) Create(ctx context.Context, req resource.Create
 				return
 			}
 
-			getEntriesResponse, err := r.client.GetRegionalPolicyEntries(getResponse.Moniker)
+			// Read the regional policy again so the version recorded is the one after the entries were added
+			getResponse, err = r.client.GetRegionalPolicy(ctx, createResponse.Data)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error re-reading regional policy",
+					"Could not read regional policy, unexpected error: "+err.Error(),
+				)
+				return
+			}
+
+			getEntriesResponse, err := r.client.GetRegionalPolicyEntries(ctx, getResponse.Moniker)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error getting regional policy",
@@ -277,12 +299,14 @@ func (r *regionalPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
+
 	// Get refreshed regional policy values
-	apiResponse, err := r.client.GetRegionalPolicy(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetRegionalPolicy(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -294,7 +318,7 @@ func (r *regionalPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	entryResponse, err := r.client.GetRegionalPolicyEntries(state.Moniker.ValueString())
+	entryResponse, err := r.client.GetRegionalPolicyEntries(ctx, key)
 	if err != nil {
 
 		resp.Diagnostics.AddError(
@@ -361,9 +385,19 @@ func (r *regionalPolicyResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	// Update existing regional policy
-	_, err = r.client.UpdateRegionalPolicy(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Update existing regional policy, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+	_, err = r.client.UpdateRegionalPolicy(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "regional policy", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating regional policy Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update regional policy, unexpected error: "+err.Error(),
@@ -371,21 +405,32 @@ func (r *regionalPolicyResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	// Add entries
-	if apiConfigDataModel.Entries != nil && len(*apiConfigDataModel.Entries) > 0 {
-		_, err = r.client.AddRegionalPolicyEntries(*apiConfigDataModel.Entries, plan.Moniker.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error adding regional policy entries Info Moniker:"+plan.Moniker.ValueString(),
-				"Could not update regional policy entries, unexpected error: "+err.Error(),
-			)
-			return
-		}
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
+	// Reconcile entries, applying only the adds, changes and removals needed
+	currentEntries, err := r.client.GetRegionalPolicyEntries(ctx, key)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading regional policy Entries",
+			"Could not read regional policy entries, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	desiredEntries := []models.RegionalPolicyEntryModifyItem{}
+	if apiConfigDataModel.Entries != nil {
+		desiredEntries = *apiConfigDataModel.Entries
+	}
+
+	r.reconcileEntries(ctx, key, currentEntries, desiredEntries, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Fetch updated regional policy to update state
 	// populated.
-	apiResponse, err := r.client.GetRegionalPolicy(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetRegionalPolicy(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading regional policy Info",
@@ -394,7 +439,7 @@ func (r *regionalPolicyResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	entriesResponse, err := r.client.GetRegionalPolicyEntries(plan.Moniker.ValueString())
+	entriesResponse, err := r.client.GetRegionalPolicyEntries(ctx, key)
 	if err != nil {
 
 		resp.Diagnostics.AddError(
@@ -440,6 +485,57 @@ func (r *regionalPolicyResource) Update(ctx context.Context, req resource.Update
 	}
 }
 
+// reconcileEntries updates the entries of the policy with the given id or moniker, matched by iso3 and operator id, to the desired ones.
+func (r *regionalPolicyResource) reconcileEntries(ctx context.Context, key string, current []models.RegionalPolicyEntry, desired []models.RegionalPolicyEntryModifyItem, diags *diag.Diagnostics) {
+	diff := diffEntries(current, desired, entryKeys[models.RegionalPolicyEntry, models.RegionalPolicyEntryModifyItem]{
+		current: func(entry models.RegionalPolicyEntry) string {
+			return countryOperatorKey(entry.Iso3, entry.OperatorId)
+		},
+		desired: func(entry models.RegionalPolicyEntryModifyItem) string {
+			return countryOperatorKey(entry.Iso3, entry.OperatorId)
+		},
+		id: func(entry models.RegionalPolicyEntry) string {
+			return entry.Id
+		},
+		same: func(existing models.RegionalPolicyEntry, entry models.RegionalPolicyEntryModifyItem) bool {
+			return entry.RegionalGatewayId != nil && existing.RegionalGateway.Moniker == *entry.RegionalGatewayId
+		},
+	})
+
+	for _, entryId := range diff.Remove {
+		_, err := r.client.DeleteRegionalPolicyEntry(ctx, key, entryId)
+		if err != nil && !stacuity.IsNotFound(err) {
+			diags.AddError(
+				"Error removing regional policy entry Info:"+key,
+				"Could not remove regional policy entry "+entryId+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	for _, change := range diff.Change {
+		_, err := r.client.UpdateRegionalPolicyEntry(ctx, key, change.Id, change.Entry)
+		if err != nil {
+			diags.AddError(
+				"Error updating regional policy entry Info:"+key,
+				"Could not update regional policy entry "+change.Id+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if len(diff.Add) > 0 {
+		_, err := r.client.AddRegionalPolicyEntries(ctx, diff.Add, key)
+		if err != nil {
+			diags.AddError(
+				"Error adding regional policy entries Info:"+key,
+				"Could not update regional policy entries, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *regionalPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Retrieve values from state
@@ -449,11 +545,17 @@ func (r *regionalPolicyResource) Delete(ctx context.Context, req resource.Delete
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing regional policy
-	result, err := r.client.DeleteRegionalPolicy(state.Moniker.ValueString())
+	result, err := r.client.DeleteRegionalPolicy(ctx, key)
 
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting regional policy",
 			"Could not delete regional policy, unexpected error: "+err.Error(),
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"stacuity.com/go_client/fake"
+)
+
+func TestRegionalPolicyResourceEntries(t *testing.T) {
+	server := newFakeServer(t)
+
+	europeGBR := `{ iso_3 = "GBR", regional_gateway_id = "eu-west" }`
+	americasGBR := `{ iso_3 = "GBR", regional_gateway_id = "us-east" }`
+	europeOperator := `{ iso_3 = "DZA", operator_id = 2145, regional_gateway_id = "eu-west" }`
+	americasDefault := `{ regional_gateway_id = "us-east" }`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeProviderConfig(server) + testRegionalPolicyConfig(americasDefault, europeGBR),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("stacuity_regional_policy.test", "entries.#", "2"),
+					testCheckRegionalPolicyEntries(server, "*/*=us-east", "GBR/*=eu-west"),
+					testCheckRegionalPolicyVersion(server),
+				),
+			},
+			// Add only
+			{
+				Config: testFakeProviderConfig(server) + testRegionalPolicyConfig(americasDefault, europeGBR, europeOperator),
+				Check:  testCheckRegionalPolicyEntries(server, "*/*=us-east", "GBR/*=eu-west", "DZA/2145=eu-west"),
+			},
+			// Changed entry
+			{
+				Config: testFakeProviderConfig(server) + testRegionalPolicyConfig(americasDefault, americasGBR, europeOperator),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("stacuity_regional_policy.test", tfjsonpath.New("version")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckRegionalPolicyEntries(server, "*/*=us-east", "GBR/*=us-east", "DZA/2145=eu-west"),
+					testCheckRegionalPolicyVersion(server),
+				),
+			},
+			// Remove only, including the country and operator mapping
+			{
+				Config: testFakeProviderConfig(server) + testRegionalPolicyConfig(americasDefault, americasGBR),
+				Check:  testCheckRegionalPolicyEntries(server, "*/*=us-east", "GBR/*=us-east"),
+			},
+			// No-op
+			{
+				Config: testFakeProviderConfig(server) + testRegionalPolicyConfig(americasDefault, americasGBR),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("stacuity_regional_policy.test", plancheck.ResourceActionNoop),
+						plancheck.ExpectKnownValue("stacuity_regional_policy.test", tfjsonpath.New("version"), knownvalue.NotNull()),
+					},
+				},
+				Check: testCheckRegionalPolicyEntries(server, "*/*=us-east", "GBR/*=us-east"),
+			},
+		},
+	})
+}
+
+func testRegionalPolicyConfig(entries ...string) string {
+	return fmt.Sprintf(`
+resource "stacuity_regional_policy" "test" {
+  name    = "Terraform regional policy"
+  moniker = "tf-regional-policy"
+  entries = [%s]
+}
+`, strings.Join(entries, ", "))
+}
+
+// testCheckRegionalPolicyEntries checks the entries held by the fake API, each given as
+// country/operator=gateway.
+func testCheckRegionalPolicyEntries(server *fake.Server, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		entries, err := server.APIClient().GetRegionalPolicyEntries(context.Background(), "tf-regional-policy")
+		if err != nil {
+			return err
+		}
+
+		got := []string{}
+		for _, entry := range entries {
+			got = append(got, countryOperatorKey(entry.Iso3, entry.OperatorId)+"="+entry.RegionalGateway.Moniker)
+		}
+
+		slices.Sort(got)
+		want = slices.Sorted(slices.Values(want))
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("regional policy has entries %v, want %v", got, want)
+		}
+
+		return nil
+	}
+}
+
+// testCheckRegionalPolicyVersion checks that the version in state is the one held by the fake API.
+func testCheckRegionalPolicyVersion(server *fake.Server) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		policy, err := server.APIClient().GetRegionalPolicy(context.Background(), "tf-regional-policy")
+		if err != nil {
+			return err
+		}
+
+		return resource.TestCheckResourceAttr("stacuity_regional_policy.test", "version", policy.Version)(state)
+	}
+}
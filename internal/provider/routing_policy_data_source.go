@@ -60,6 +60,7 @@ func (d *routingPolicyDataSource) Metadata(_ context.Context, req datasource.Met
 type routingPolicysDataSourceModel struct {
 	RoutingPolicies []routingPolicyReadModel `tfsdk:"routingpolicies"`
 	Filter          types.Object             `tfsdk:"filter"`
+	All             types.Bool               `tfsdk:"all"`
 }
 
 // routingPolicyReadModel maps schema data.
@@ -421,6 +422,10 @@ func (d *routingPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaR
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -464,7 +469,12 @@ func (d *routingPolicyDataSource) Read(ctx context.Context, req datasource.ReadR
 		}
 	}
 
-	routingPolicies, err := d.client.GetRoutingPolicies(pagingQuery)
+	getRoutingPolicies := d.client.GetRoutingPolicies
+	if state.All.ValueBool() {
+		getRoutingPolicies = d.client.GetAllRoutingPolicies
+	}
+
+	routingPolicies, err := getRoutingPolicies(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity routing policies",
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -17,13 +18,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	stacuity "stacuity.com/go_client"
 	models "stacuity.com/go_client/models"
+	"terraform-provider-stacuity/internal/routingpolicy"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &routingPolicyResource{}
-	_ resource.ResourceWithConfigure   = &routingPolicyResource{}
-	_ resource.ResourceWithImportState = &routingPolicyResource{}
+	_ resource.Resource                   = &routingPolicyResource{}
+	_ resource.ResourceWithConfigure      = &routingPolicyResource{}
+	_ resource.ResourceWithImportState    = &routingPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &routingPolicyResource{}
+	_ resource.ResourceWithModifyPlan     = &routingPolicyResource{}
 )
 
 // NewRoutingPolicyResource is a helper function to simplify the provider implementation.
@@ -38,6 +42,7 @@ type routingPolicyResource struct {
 
 type routingPolicyResourceModel struct {
 	Id                              types.String        `tfsdk:"id"`
+	Version                         types.String        `tfsdk:"version"`
 	Name                            types.String        `tfsdk:"name"`
 	Moniker                         types.String        `tfsdk:"moniker"`
 	VSlice                          types.String        `tfsdk:"vslice"`
@@ -74,8 +79,169 @@ type EdgeServiceModel struct {
 }
 
 func (r *routingPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "routing policy", r.client.GetRoutingPolicy, func(item models.RoutingPolicyReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
+}
+
+// ValidateConfig warns about duplicate rules, rules of which only one can ever apply and rules
+// that contradict each other. The rules are a set, whose order Terraform does not keep, so only
+// findings that hold whichever rule takes precedence are reported.
+func (r routingPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rulesSet types.Set
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("routing_policy_rules"), &rulesSet)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unknown rules cannot be compared.
+	if rulesSet.IsNull() || rulesSet.IsUnknown() {
+		return
+	}
+
+	var data []RoutingRuleModel
+	resp.Diagnostics.Append(rulesSet.ElementsAs(ctx, &data, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules := make([]routingpolicy.Rule, 0, len(data))
+	for _, rule := range data {
+		if hasUnknownMatchValues(rule) {
+			return
+		}
+		rules = append(rules, routingPolicyRule(rule, 0))
+	}
+
+	findings, errs := routingpolicy.AnalyzeUnordered(rules)
+	for _, err := range errs {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("routing_policy_rules"),
+			"Routing policy rule not analysed",
+			fmt.Sprintf("Rule %q could not be checked for duplicates and conflicts: %s", rules[err.Index].Description, err.Err),
+		)
+	}
+
+	for _, finding := range findings {
+		rule, other := rules[finding.Index], rules[finding.Earlier]
+
+		switch finding.Kind {
+		case routingpolicy.Duplicate:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("routing_policy_rules"),
+				"Duplicate routing policy rules",
+				fmt.Sprintf("Rules %q and %q match the same flows and do the same with them, so only one of them ever applies.", other.Description, rule.Description),
+			)
+		case routingpolicy.Shadowed:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("routing_policy_rules"),
+				"Unreachable routing policy rule",
+				fmt.Sprintf("Rules %q (%s) and %q (%s) match the same flows but do different things with them, so whichever "+
+					"takes precedence, the other never applies.",
+					other.Description, routingRuleOutcome(other), rule.Description, routingRuleOutcome(rule)),
+			)
+		case routingpolicy.Conflict:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("routing_policy_rules"),
+				"Conflicting routing policy rules",
+				fmt.Sprintf("Rules %q (%s) and %q (%s) match some of the same flows. Which of them applies to those flows depends on "+
+					"the order the rules are sent to the API in, which Terraform does not keep for a set.",
+					other.Description, other.RuleAction, rule.Description, rule.RuleAction),
+			)
+		}
+	}
+}
+
+// ModifyPlan warns about rules that never apply in the order they are sent to the API, which
+// gives each rule its precedence from its position. Terraform keeps the elements of a set in an
+// order of its own, which is only settled once every value in the rules is known. ValidateConfig
+// already reports the rules that never apply in any order, so only rules hidden by a broader
+// rule ahead of them are reported here.
+func (r *routingPolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is sent when the routing policy is destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var rulesSet types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("routing_policy_rules"), &rulesSet)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rulesValue, err := rulesSet.ToTerraformValue(ctx)
+	if err != nil || !rulesValue.IsFullyKnown() || rulesSet.IsNull() {
+		return
+	}
+
+	var data []RoutingRuleModel
+	resp.Diagnostics.Append(rulesSet.ElementsAs(ctx, &data, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules := make([]routingpolicy.Rule, 0, len(data))
+	for index, rule := range data {
+		rules = append(rules, routingPolicyRule(rule, int32(index+1)))
+	}
+
+	unordered, _ := routingpolicy.AnalyzeUnordered(rules)
+	reported := make(map[[2]int]bool, len(unordered))
+	for _, finding := range unordered {
+		if finding.Kind != routingpolicy.Conflict {
+			reported[routingRulePair(finding)] = true
+		}
+	}
+
+	findings, _ := routingpolicy.Analyze(rules)
+	for _, finding := range findings {
+		if finding.Kind != routingpolicy.Shadowed || reported[routingRulePair(finding)] {
+			continue
+		}
+
+		rule, earlier := rules[finding.Index], rules[finding.Earlier]
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("routing_policy_rules"),
+			"Unreachable routing policy rule",
+			fmt.Sprintf("Rule %q (%s) never applies: rule %q (%s) matches every flow it does and takes precedence over it "+
+				"in the order the rules are sent to the API. Narrow rule %q, or give the rules patterns that do not overlap.",
+				rule.Description, routingRuleOutcome(rule), earlier.Description, routingRuleOutcome(earlier), earlier.Description),
+		)
+	}
+}
+
+// routingRulePair returns the positions of the two rules of a finding, lowest first.
+func routingRulePair(finding routingpolicy.Finding) [2]int {
+	return [2]int{min(finding.Index, finding.Earlier), max(finding.Index, finding.Earlier)}
+}
+
+// routingRuleOutcome describes what a rule does with the flows it matches.
+func routingRuleOutcome(rule routingpolicy.Rule) string {
+	if rule.RoutingTarget == "" {
+		return rule.RuleAction
+	}
+
+	return rule.RuleAction + " to " + rule.RoutingTarget
+}
+
+// hasUnknownMatchValues reports whether any attribute deciding which flows a rule applies to,
+// or what it does with them, is not yet known.
+func hasUnknownMatchValues(rule RoutingRuleModel) bool {
+	values := []attr.Value{
+		rule.RuleAction, rule.RuleDirection, rule.SourceIpPattern, rule.DestinationIpPattern, rule.TransportProtocol,
+		rule.SourcePortPattern, rule.DestinationPortPattern, rule.RoutingTarget, rule.Reflexive, rule.Enabled,
+	}
+
+	for _, value := range values {
+		if value.IsUnknown() {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (r *routingPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -95,6 +261,13 @@ func (r *routingPolicyResource) Schema(ctx context.Context, req resource.SchemaR
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the routing policy as last read from the API. Updates are rejected if the routing policy has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the routing policy.",
 				Required:    true,
@@ -118,7 +291,7 @@ func (r *routingPolicyResource) Schema(ctx context.Context, req resource.SchemaR
 				Required:    true,
 			},
 			"routing_policy_rules": schema.SetNestedAttribute{
-				Description: "List of rules for the routing policy.",
+				Description: "List of rules for the routing policy. Duplicate rules, rules that never apply because another rule matches the same flows or takes precedence over them, and forward and drop rules matching some of the same flows, are reported as warnings.",
 				Optional:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -266,8 +439,9 @@ func (r *routingPolicyResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	// Create new routing policy
-	apiResponse, err := r.client.CreateRoutingPolicy(apiData)
+	// Create new routing policy, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	apiResponse, err := r.client.CreateRoutingPolicy(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating routing policy",
@@ -277,7 +451,7 @@ func (r *routingPolicyResource) Create(ctx context.Context, req resource.CreateR
 	}
 
 	if apiResponse.Success {
-		getResponse, err := r.client.GetRoutingPolicy(apiResponse.Data)
+		getResponse, err := r.client.GetRoutingPolicy(ctx, apiResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading routing policy",
@@ -357,12 +531,14 @@ func (r *routingPolicyResource) Read(ctx context.Context, req resource.ReadReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
+
 	// Get refreshed routing policy values
-	apiResponse, err := r.client.GetRoutingPolicy(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetRoutingPolicy(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -454,9 +630,19 @@ func (r *routingPolicyResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	// Update existing routing policy
-	_, err = r.client.UpdateRoutingPolicy(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Update existing routing policy, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+	_, err = r.client.UpdateRoutingPolicy(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "routing policy", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating routing policy Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update routing policy, unexpected error: "+err.Error(),
@@ -464,9 +650,12 @@ func (r *routingPolicyResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
 	// Fetch updated routing policy to update state
 	// populated.
-	apiResponse, err := r.client.GetRoutingPolicy(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetRoutingPolicy(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading routing policy Info",
@@ -543,11 +732,17 @@ func (r *routingPolicyResource) Delete(ctx context.Context, req resource.DeleteR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing routing policy
-	result, err := r.client.DeleteRoutingPolicy(state.Moniker.ValueString())
+	result, err := r.client.DeleteRoutingPolicy(ctx, key)
 
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting routing policy",
 			"Could not delete routing policy, unexpected error: "+err.Error(),
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testRoutingPolicyRule is a rule of a routing policy configuration, with the attributes the
+// tests vary. Unset attributes are null.
+type testRoutingPolicyRule struct {
+	description, action, destinationIPs, routingTarget string
+	unknownTarget                                      bool
+}
+
+// testRoutingPolicyValue returns a routing policy configuration holding rules in the given
+// order, along with its schema.
+func testRoutingPolicyValue(t *testing.T, rules ...testRoutingPolicyRule) (tftypes.Value, tfsdk.Config) {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	NewRoutingPolicyResource().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	rulesType := objectType.AttributeTypes["routing_policy_rules"].(tftypes.Set)
+	ruleType := rulesType.ElementType.(tftypes.Object)
+
+	nulls := func(objectType tftypes.Object) map[string]tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+		return values
+	}
+
+	var ruleValues []tftypes.Value
+	for _, rule := range rules {
+		values := nulls(ruleType)
+		values["description"] = tftypes.NewValue(tftypes.String, rule.description)
+		values["rule_action"] = tftypes.NewValue(tftypes.String, rule.action)
+		values["rule_direction"] = tftypes.NewValue(tftypes.String, "uplink")
+		values["transport_protocol"] = tftypes.NewValue(tftypes.String, "tcp")
+		values["destination_ip_pattern"] = tftypes.NewValue(tftypes.String, rule.destinationIPs)
+		values["enabled"] = tftypes.NewValue(tftypes.Bool, true)
+		if rule.routingTarget != "" {
+			values["routing_target"] = tftypes.NewValue(tftypes.String, rule.routingTarget)
+		}
+		if rule.unknownTarget {
+			values["routing_target"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+		}
+		ruleValues = append(ruleValues, tftypes.NewValue(ruleType, values))
+	}
+
+	values := nulls(objectType)
+	values["routing_policy_rules"] = tftypes.NewValue(rulesType, ruleValues)
+	value := tftypes.NewValue(objectType, values)
+
+	return value, tfsdk.Config{Schema: schemaResp.Schema, Raw: value}
+}
+
+// warningSummaries returns the summaries of the warnings in diags.
+func warningSummaries(diags diag.Diagnostics) []string {
+	var summaries []string
+	for _, warning := range diags.Warnings() {
+		summaries = append(summaries, warning.Summary())
+	}
+
+	return summaries
+}
+
+func TestRoutingPolicyResourceRuleWarnings(t *testing.T) {
+	web := testRoutingPolicyRule{description: "web", action: "forward", destinationIPs: "10.0.0.0/24", routingTarget: "internet"}
+	webHost := testRoutingPolicyRule{description: "web host", action: "forward", destinationIPs: "10.0.0.5", routingTarget: "vpn"}
+	dropWeb := testRoutingPolicyRule{description: "drop web", action: "drop", destinationIPs: "10.0.0.0/24"}
+	dropHost := testRoutingPolicyRule{description: "drop host", action: "drop", destinationIPs: "10.0.0.5"}
+	other := testRoutingPolicyRule{description: "other", action: "forward", destinationIPs: "192.168.0.0/24"}
+
+	webElsewhere := web
+	webElsewhere.description, webElsewhere.routingTarget = "web elsewhere", "vpn"
+
+	webAgain := web
+	webAgain.description = "web again"
+
+	webUnknown := webAgain
+	webUnknown.routingTarget, webUnknown.unknownTarget = "", true
+
+	tests := map[string]struct {
+		rules        []testRoutingPolicyRule
+		wantValidate []string
+		wantPlan     []string
+	}{
+		"no findings": {
+			rules: []testRoutingPolicyRule{web, other},
+		},
+		"duplicate": {
+			rules:        []testRoutingPolicyRule{web, webAgain},
+			wantValidate: []string{"Duplicate routing policy rules"},
+		},
+		"same flows to another target": {
+			rules:        []testRoutingPolicyRule{web, webElsewhere},
+			wantValidate: []string{"Unreachable routing policy rule"},
+		},
+		"same flows forwarded and dropped": {
+			rules:        []testRoutingPolicyRule{web, dropWeb},
+			wantValidate: []string{"Unreachable routing policy rule"},
+		},
+		"narrower rule sent after a broader one": {
+			rules:    []testRoutingPolicyRule{web, webHost},
+			wantPlan: []string{"Unreachable routing policy rule"},
+		},
+		"narrower rule sent first": {
+			rules: []testRoutingPolicyRule{webHost, web},
+		},
+		"narrower contradictory rule sent after a broader one": {
+			rules:        []testRoutingPolicyRule{web, dropHost},
+			wantValidate: []string{"Conflicting routing policy rules"},
+			wantPlan:     []string{"Unreachable routing policy rule"},
+		},
+		"unknown routing target": {
+			rules: []testRoutingPolicyRule{web, webUnknown},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			value, config := testRoutingPolicyValue(t, test.rules...)
+			r := &routingPolicyResource{}
+
+			validateResp := &resource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: config}, validateResp)
+			if validateResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected validation errors: %v", validateResp.Diagnostics)
+			}
+			if got := warningSummaries(validateResp.Diagnostics); !slices.Equal(got, test.wantValidate) {
+				t.Errorf("got validation warnings %q, want %q", got, test.wantValidate)
+			}
+
+			plan := tfsdk.Plan{Schema: config.Schema, Raw: value}
+			planResp := &resource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(ctx, resource.ModifyPlanRequest{Config: config, Plan: plan}, planResp)
+			if planResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected plan errors: %v", planResp.Diagnostics)
+			}
+			if got := warningSummaries(planResp.Diagnostics); !slices.Equal(got, test.wantPlan) {
+				t.Errorf("got plan warnings %q, want %q", got, test.wantPlan)
+			}
+		})
+	}
+}
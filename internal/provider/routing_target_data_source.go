@@ -60,6 +60,7 @@ func (d *routingTargetDataSource) Metadata(_ context.Context, req datasource.Met
 type routingTargetDataSourceModel struct {
 	RoutingTargets []routingTargetReadModel `tfsdk:"routing_targets"`
 	Filter         types.Object             `tfsdk:"filter"`
+	All            types.Bool               `tfsdk:"all"`
 }
 
 // routingTargetReadModel maps schema data.
@@ -190,6 +191,10 @@ func (d *routingTargetDataSource) Schema(_ context.Context, _ datasource.SchemaR
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -233,7 +238,12 @@ func (d *routingTargetDataSource) Read(ctx context.Context, req datasource.ReadR
 		}
 	}
 
-	routingTargets, err := d.client.GetRoutingTargets(pagingQuery)
+	getRoutingTargets := d.client.GetRoutingTargets
+	if state.All.ValueBool() {
+		getRoutingTargets = d.client.GetAllRoutingTargets
+	}
+
+	routingTargets, err := getRoutingTargets(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity Routing Target",
@@ -7,13 +7,16 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	stacuity "stacuity.com/go_client"
 	models "stacuity.com/go_client/models"
@@ -38,6 +41,7 @@ type routingTargetResource struct {
 
 type routingTargetResourceModel struct {
 	Id                           types.String            `tfsdk:"id"`
+	Version                      types.String            `tfsdk:"version"`
 	Name                         types.String            `tfsdk:"name"`
 	Moniker                      types.String            `tfsdk:"moniker"`
 	RoutingTargetType            types.String            `tfsdk:"routing_target_type"`
@@ -59,6 +63,8 @@ type RoutingTargetVpn struct {
 	LocalEncryptionDomain  types.String `tfsdk:"local_encryption_domain"`
 	LocalSubnets           types.String `tfsdk:"local_subnets"`
 	PresharedKey           types.String `tfsdk:"preshared_key"`
+	PresharedKeyWO         types.String `tfsdk:"preshared_key_wo"`
+	PresharedKeyWOVersion  types.Int32  `tfsdk:"preshared_key_wo_version"`
 	KeyExchangeType        types.String `tfsdk:"key_exchange_type"`
 	VpnIkeOption           types.String `tfsdk:"vpn_ike_option"`
 	VpnEspOption           types.String `tfsdk:"vpn_esp_option"`
@@ -67,17 +73,29 @@ type RoutingTargetVpn struct {
 }
 
 type RoutingTargetWireguard struct {
-	LocalPublicKey       types.String `tfsdk:"local_public_key"`
-	LocalSubnets         types.String `tfsdk:"local_subnets"`
-	RemotePublicKey      types.String `tfsdk:"remote_public_key"`
-	RemoteSubnets        types.String `tfsdk:"remote_subnets"`
-	RemotePeerIPAddress  types.String `tfsdk:"remote_peer_ip_address"`
-	RemotePeerPortNumber types.Int32  `tfsdk:"remote_peer_port_number"`
+	LocalPublicKey           types.String `tfsdk:"local_public_key"`
+	LocalSubnets             types.String `tfsdk:"local_subnets"`
+	RemotePublicKey          types.String `tfsdk:"remote_public_key"`
+	RemotePublicKeyWO        types.String `tfsdk:"remote_public_key_wo"`
+	RemotePublicKeyWOVersion types.Int32  `tfsdk:"remote_public_key_wo_version"`
+	RemoteSubnets            types.String `tfsdk:"remote_subnets"`
+	RemotePeerIPAddress      types.String `tfsdk:"remote_peer_ip_address"`
+	RemotePeerPortNumber     types.Int32  `tfsdk:"remote_peer_port_number"`
 }
 
+// The routing target attributes that can also be set write-only. The WireGuard remote public
+// key is not secret, but a write-only variant lets it come from an ephemeral resource.
+var (
+	presharedKeySecret    = writeOnlySecret{parent: path.Root("configuration_data").AtName("vpn_config"), name: "preshared_key"}
+	remotePublicKeySecret = writeOnlySecret{parent: path.Root("configuration_data").AtName("wireguard_config"), name: "remote_public_key"}
+	routingTargetSecrets  = []writeOnlySecret{presharedKeySecret, remotePublicKeySecret}
+)
+
 func (r *routingTargetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "routing target", r.client.GetRoutingTarget, func(item models.RoutingTargetReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *routingTargetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -97,6 +115,13 @@ func (r *routingTargetResource) Schema(ctx context.Context, req resource.SchemaR
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the routing target as last read from the API. Updates are rejected if the routing target has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the routing target",
 				Required:    true,
@@ -148,8 +173,26 @@ func (r *routingTargetResource) Schema(ctx context.Context, req resource.SchemaR
 								Optional:    true,
 							},
 							"preshared_key": schema.StringAttribute{
-								Description: "Preshared key for VPN.",
+								Description: "Preshared key for VPN. Stored in state; set preshared_key_wo instead to keep it out of state.",
 								Optional:    true,
+								Sensitive:   true,
+							},
+							"preshared_key_wo": schema.StringAttribute{
+								Description: "Preshared key for VPN. Write-only, so it is never stored in state. Requires Terraform 1.11 or later. Change preshared_key_wo_version to send a new value.",
+								Optional:    true,
+								Sensitive:   true,
+								WriteOnly:   true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("preshared_key")),
+									stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("preshared_key_wo_version")),
+								},
+							},
+							"preshared_key_wo_version": schema.Int32Attribute{
+								Description: "Version of preshared_key_wo. Change it to send a new write-only value.",
+								Optional:    true,
+								Validators: []validator.Int32{
+									int32validator.AlsoRequires(path.MatchRelative().AtParent().AtName("preshared_key_wo")),
+								},
 							},
 							"key_exchange_type": schema.StringAttribute{
 								Description: "Key exchange type for VPN.",
@@ -164,11 +207,11 @@ func (r *routingTargetResource) Schema(ctx context.Context, req resource.SchemaR
 								Optional:    true,
 							},
 							"phase1_lifetime": schema.Int32Attribute{
-								Description: "Phase 1 lifetime for VPN IKE negotiation.",
+								Description: "Phase 1 lifetime for VPN IKE negotiation, in seconds.",
 								Optional:    true,
 							},
 							"phase2_lifetime": schema.Int32Attribute{
-								Description: "Phase 2 lifetime for VPN IKE negotiation.",
+								Description: "Phase 2 lifetime for VPN IKE negotiation, in seconds.",
 								Optional:    true,
 							},
 						},
@@ -190,6 +233,22 @@ func (r *routingTargetResource) Schema(ctx context.Context, req resource.SchemaR
 								Description: "Remote public key for WireGuard.",
 								Optional:    true,
 							},
+							"remote_public_key_wo": schema.StringAttribute{
+								Description: "Remote public key for WireGuard. Write-only, so it can be taken from the stacuity_wireguard_key_pair ephemeral resource. Requires Terraform 1.11 or later. Change remote_public_key_wo_version to send a new value.",
+								Optional:    true,
+								WriteOnly:   true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("remote_public_key")),
+									stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("remote_public_key_wo_version")),
+								},
+							},
+							"remote_public_key_wo_version": schema.Int32Attribute{
+								Description: "Version of remote_public_key_wo. Change it to send a new write-only value.",
+								Optional:    true,
+								Validators: []validator.Int32{
+									int32validator.AlsoRequires(path.MatchRelative().AtParent().AtName("remote_public_key_wo")),
+								},
+							},
 							"remote_subnets": schema.StringAttribute{
 								Description: "Remote subnets for WireGuard.",
 								Optional:    true,
@@ -264,8 +323,15 @@ func (r *routingTargetResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	// Create new routing target
-	apiResponse, err := r.client.CreateRoutingTarget(apiData)
+	// Write-only secrets are never in the plan, only in the configuration
+	applyRoutingTargetWriteOnly(ctx, req.Config, &apiData, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new routing target, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	apiResponse, err := r.client.CreateRoutingTarget(createCtx, apiData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating routing target",
@@ -275,7 +341,7 @@ func (r *routingTargetResource) Create(ctx context.Context, req resource.CreateR
 	}
 
 	if apiResponse.Success {
-		getResponse, err := r.client.GetRoutingTarget(apiResponse.Data)
+		getResponse, err := r.client.GetRoutingTarget(ctx, apiResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading routing target",
@@ -305,6 +371,9 @@ func (r *routingTargetResource) Create(ctx context.Context, req resource.CreateR
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	for _, secret := range routingTargetSecrets {
+		secret.syncState(ctx, req.Plan, &resp.State, &resp.Diagnostics)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -320,12 +389,14 @@ func (r *routingTargetResource) Read(ctx context.Context, req resource.ReadReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
+
 	// Get refreshed routing target values
-	apiResponse, err := r.client.GetRoutingTarget(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetRoutingTarget(ctx, key)
 
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -359,12 +430,37 @@ func (r *routingTargetResource) Read(ctx context.Context, req resource.ReadReque
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+	for _, secret := range routingTargetSecrets {
+		secret.syncState(ctx, req.State, &resp.State, &resp.Diagnostics)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
 
+// applyRoutingTargetWriteOnly copies write-only values from the configuration into the API request.
+func applyRoutingTargetWriteOnly(ctx context.Context, config tfsdk.Config, apiData *models.RoutingTargetModifyItem, diags *diag.Diagnostics) {
+	if apiData.ConfigurationData == nil {
+		return
+	}
+
+	if vpn := apiData.ConfigurationData.VpnConfig; vpn != nil {
+		if presharedKey := presharedKeySecret.configValue(ctx, config, diags); !presharedKey.IsNull() {
+			vpn.PresharedKey = presharedKey.ValueString()
+		}
+	}
+	if wireGuard := apiData.ConfigurationData.WireGuardConfig; wireGuard != nil {
+		if remotePublicKey := remotePublicKeySecret.configValue(ctx, config, diags); !remotePublicKey.IsNull() {
+			wireGuard.RemotePublicKey = remotePublicKey.ValueString()
+		}
+	}
+}
+
 func handleEmptyConfigData(configDataModel *routingTargetResourceModel) {
+	if configDataModel.ConfigurationData == nil {
+		return
+	}
+
 	if configDataModel.ConfigurationData.VpnConfig == nil && configDataModel.ConfigurationData.WireGuardConfig == nil {
 		configDataModel.ConfigurationData = nil
 	}
@@ -391,9 +487,44 @@ func (r *routingTargetResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	// Update existing routing target
-	_, err = r.client.UpdateRoutingTarget(plan.Moniker.ValueString(), apiConfigDataModel)
+	// Write-only secrets are never in the plan, only in the configuration
+	applyRoutingTargetWriteOnly(ctx, req.Config, &apiConfigDataModel, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update existing routing target, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+
+	// A key pair from stacuity_wireguard_key_pair is new on every run, so a write-only remote
+	// public key is only replaced when its version changes
+	configData := apiConfigDataModel.ConfigurationData
+	if configData != nil && configData.WireGuardConfig != nil && remotePublicKeySecret.keepCurrent(ctx, req.Plan, req.State, &resp.Diagnostics) {
+		current, err := r.client.GetRoutingTarget(ctx, key)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading routing target Info",
+				"Could not read routing target Moniker "+moniker.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+
+		if current.ConfigurationData != nil && current.ConfigurationData.WireGuardConfig != nil {
+			configData.WireGuardConfig.RemotePublicKey = current.ConfigurationData.WireGuardConfig.RemotePublicKey
+		}
+	}
+
+	_, err = r.client.UpdateRoutingTarget(ctx, key, apiConfigDataModel, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "routing target", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating routing target Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update routing target, unexpected error: "+err.Error(),
@@ -401,9 +532,12 @@ func (r *routingTargetResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
 	// Fetch updated routing target to update state
 	// populated.
-	apiResponse, err := r.client.GetRoutingTarget(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetRoutingTarget(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading routing target Info",
@@ -433,6 +567,9 @@ func (r *routingTargetResource) Update(ctx context.Context, req resource.UpdateR
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	for _, secret := range routingTargetSecrets {
+		secret.syncState(ctx, req.Plan, &resp.State, &resp.Diagnostics)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -447,10 +584,16 @@ func (r *routingTargetResource) Delete(ctx context.Context, req resource.DeleteR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing routing target
-	result, err := r.client.DeleteRoutingTarget(state.Moniker.ValueString())
+	result, err := r.client.DeleteRoutingTarget(ctx, key)
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting routing target",
 			"Could not delete routing target, unexpected error: "+err.Error(),
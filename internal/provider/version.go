@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// versionUnlessChanged - A plan modifier for the version attribute that keeps the version from
+// the prior state when no other attribute of the object is planned to change. Every update
+// gives the object a new version, so the version is only left unknown when there is one.
+func versionUnlessChanged() planmodifier.String {
+	return versionUnlessChangedModifier{}
+}
+
+type versionUnlessChangedModifier struct{}
+
+func (m versionUnlessChangedModifier) Description(ctx context.Context) string {
+	return "Keeps the version from the prior state unless the object is planned to change."
+}
+
+func (m versionUnlessChangedModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m versionUnlessChangedModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to keep on create, and nothing to do if the version is already known
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var planned, prior map[string]tftypes.Value
+	if err := req.Plan.Raw.As(&planned); err != nil {
+		return
+	}
+	if err := req.State.Raw.As(&prior); err != nil {
+		return
+	}
+
+	for name, value := range planned {
+		if name == "version" {
+			continue
+		}
+		if !value.IsFullyKnown() || !value.Equal(prior[name]) {
+			return
+		}
+	}
+
+	resp.PlanValue = req.StateValue
+}
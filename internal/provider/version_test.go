@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestVersionUnlessChanged(t *testing.T) {
+	testSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{Computed: true},
+			"name":    schema.StringAttribute{Required: true},
+		},
+	}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"version": tftypes.String, "name": tftypes.String}}
+
+	object := func(version, name tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{"version": version, "name": name})
+	}
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	str := func(value string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+
+	tests := map[string]struct {
+		plan  tftypes.Value
+		state tftypes.Value
+		want  types.String
+	}{
+		"create": {
+			plan:  object(unknown, str("a")),
+			state: tftypes.NewValue(objectType, nil),
+			want:  types.StringUnknown(),
+		},
+		"unchanged": {
+			plan:  object(unknown, str("a")),
+			state: object(str("3"), str("a")),
+			want:  types.StringValue("3"),
+		},
+		"changed": {
+			plan:  object(unknown, str("b")),
+			state: object(str("3"), str("a")),
+			want:  types.StringUnknown(),
+		},
+		"other attribute unknown": {
+			plan:  object(unknown, unknown),
+			state: object(str("3"), str("a")),
+			want:  types.StringUnknown(),
+		},
+		"already known": {
+			plan:  object(str("4"), str("b")),
+			state: object(str("3"), str("a")),
+			want:  types.StringValue("4"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			plan := tfsdk.Plan{Schema: testSchema, Raw: test.plan}
+			state := tfsdk.State{Schema: testSchema, Raw: test.state}
+
+			var planValue, stateValue types.String
+			plan.GetAttribute(ctx, path.Root("version"), &planValue)
+			state.GetAttribute(ctx, path.Root("version"), &stateValue)
+
+			req := planmodifier.StringRequest{Path: path.Root("version"), Plan: plan, PlanValue: planValue, State: state, StateValue: stateValue}
+			resp := &planmodifier.StringResponse{PlanValue: planValue}
+			versionUnlessChanged().PlanModifyString(ctx, req, resp)
+
+			if !resp.PlanValue.Equal(test.want) {
+				t.Errorf("got %s, want %s", resp.PlanValue, test.want)
+			}
+		})
+	}
+}
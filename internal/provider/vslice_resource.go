@@ -39,6 +39,7 @@ type vSliceResource struct {
 
 type vSlicesResourceModel struct {
 	Id               types.String   `tfsdk:"id"`
+	Version          types.String   `tfsdk:"version"`
 	Name             types.String   `tfsdk:"name"`
 	Moniker          types.String   `tfsdk:"moniker"`
 	DNSServers       []types.String `tfsdk:"dns_servers"`
@@ -50,8 +51,10 @@ type vSlicesResourceModel struct {
 }
 
 func (r *vSliceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import Id and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Resolve the import id or moniker to the object, then let Read populate the rest
+	importObject(ctx, req, resp, "vslice", r.client.GetVSlice, func(item models.VSliceReadItem) (string, string) {
+		return item.Id, item.Moniker
+	})
 }
 
 func (r *vSliceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -114,6 +117,13 @@ func (r *vSliceResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"version": schema.StringAttribute{
+				Description: "Version of the vSlice as last read from the API. Updates are rejected if the vSlice has changed since.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					versionUnlessChanged(),
+				},
+			},
 			"name": schema.StringAttribute{
 				Description: "Name of the vSlice",
 				Required:    true,
@@ -216,8 +226,9 @@ func (r *vSliceResource) Create(ctx context.Context, req resource.CreateRequest,
 		vSlice.DNSServers = append(vSlice.DNSServers, dns.ValueString())
 	}
 
-	// Create new vSlice
-	apiResponse, err := r.client.CreateVSlice(vSlice)
+	// Create new vSlice, keyed so retries of the POST cannot create a duplicate
+	createCtx := stacuity.WithIdempotencyKey(ctx, stacuity.NewIdempotencyKey())
+	apiResponse, err := r.client.CreateVSlice(createCtx, vSlice)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating vSlice",
@@ -227,7 +238,7 @@ func (r *vSliceResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	if apiResponse.Success {
-		getResponse, err := r.client.GetVSlice(apiResponse.Data)
+		getResponse, err := r.client.GetVSlice(ctx, apiResponse.Data)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error re-reading vSlice",
@@ -238,6 +249,7 @@ func (r *vSliceResource) Create(ctx context.Context, req resource.CreateRequest,
 
 		plan = vSlicesResourceModel{
 			Id:               types.StringValue(getResponse.Id),
+			Version:          types.StringValue(getResponse.Version),
 			Moniker:          types.StringValue(getResponse.Moniker),
 			Name:             types.StringValue(getResponse.Name),
 			EventMap:         types.StringValue(getResponse.EventMap.Moniker),
@@ -273,12 +285,13 @@ func (r *vSliceResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Get refreshed vSlice values
-	apiResponse, err := r.client.GetVSlice(state.Moniker.ValueString())
+	apiResponse, err := r.client.GetVSlice(ctx, key)
 	if err != nil {
 
-		if err.Error() == "Record not found" {
+		if stacuity.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -291,6 +304,7 @@ func (r *vSliceResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	state.Id = types.StringValue(apiResponse.Id)
+	state.Version = types.StringValue(apiResponse.Version)
 	state.Moniker = types.StringValue(apiResponse.Moniker)
 	state.Name = types.StringValue(apiResponse.Name)
 	state.EventMap = types.StringValue(apiResponse.EventMap.Moniker)
@@ -341,9 +355,19 @@ func (r *vSliceResource) Update(ctx context.Context, req resource.UpdateRequest,
 		vSlice.DNSServers = append(vSlice.DNSServers, dns.ValueString())
 	}
 
-	// Update existing vSlice
-	_, err := r.client.UpdateVSlice(plan.Moniker.ValueString(), vSlice)
+	// Update existing vSlice, provided it is unchanged since it was last read
+	var id, moniker, version types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("moniker"), &moniker)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+	key := objectKey(id, moniker)
+	_, err := r.client.UpdateVSlice(ctx, key, vSlice, version.ValueString())
 	if err != nil {
+		if stacuity.IsPreconditionFailed(err) {
+			addChangedSincePlanError(&resp.Diagnostics, "vSlice", plan.Moniker.ValueString())
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Updating vSlice Info Moniker:"+plan.Moniker.ValueString(),
 			"Could not update vSlice, unexpected error: "+err.Error(),
@@ -351,9 +375,12 @@ func (r *vSliceResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// A renamed moniker takes effect with the update, so later calls use the new one if there is no id
+	key = objectKey(id, plan.Moniker)
+
 	// Fetch updated vSlice to update state
 	// populated.
-	apiResponse, err := r.client.GetVSlice(plan.Moniker.ValueString())
+	apiResponse, err := r.client.GetVSlice(ctx, key)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading vSlice Info",
@@ -364,6 +391,7 @@ func (r *vSliceResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	// Update resource state with updated items
 	plan.Id = types.StringValue(apiResponse.Id)
+	plan.Version = types.StringValue(apiResponse.Version)
 	plan.Moniker = types.StringValue(apiResponse.Moniker)
 	plan.Name = types.StringValue(apiResponse.Name)
 	plan.EventMap = types.StringValue(apiResponse.EventMap.Moniker)
@@ -374,8 +402,11 @@ func (r *vSliceResource) Update(ctx context.Context, req resource.UpdateRequest,
 		plan.SubnetAddress = types.StringValue(apiResponse.Subnets[0])
 	}
 
-	for _, dns := range apiResponse.DNSServers {
-		plan.DNSServers = append(plan.DNSServers, types.StringValue(dns))
+	plan.DNSServers = nil
+	if len(apiResponse.DNSServers) > 0 && len(apiResponse.DNSServers[0]) > 0 {
+		for _, dns := range apiResponse.DNSServers {
+			plan.DNSServers = append(plan.DNSServers, types.StringValue(dns))
+		}
 	}
 
 	diags = resp.State.Set(ctx, plan)
@@ -394,10 +425,16 @@ func (r *vSliceResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	key := objectKey(state.Id, state.Moniker)
 
 	// Delete existing vSlice
-	result, err := r.client.DeleteVSlice(state.Moniker.ValueString())
+	result, err := r.client.DeleteVSlice(ctx, key)
 	if err != nil {
+		// Already removed outside of Terraform
+		if stacuity.IsNotFound(err) {
+			return
+		}
+
 		resp.Diagnostics.AddError(
 			"Error Deleting vSlice",
 			"Could not delete vSlice, unexpected error: "+err.Error(),
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	stacuity "stacuity.com/go_client"
+	"stacuity.com/go_client/fake"
+)
+
+func TestVSliceResource(t *testing.T) {
+	server := newFakeServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckVSliceDestroy(server),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testFakeProviderConfig(server) + testVSliceConfig("Terraform vSlice"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "name", "Terraform vSlice"),
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "moniker", "terraform-test"),
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "dns_servers.#", "2"),
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "ip_allocation_type", "static"),
+					resource.TestCheckResourceAttrSet("stacuity_vslice.test", "id"),
+					resource.TestCheckResourceAttrSet("stacuity_vslice.test", "version"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "stacuity_vslice.test",
+				ImportState:       true,
+				ImportStateId:     "terraform-test",
+				ImportStateVerify: true,
+				// The API does not return the allocation type a vSlice was created with.
+				ImportStateVerifyIgnore: []string{"ip_allocation_type"},
+			},
+			// Update and Read testing
+			{
+				Config: testFakeProviderConfig(server) + testVSliceConfig("Renamed vSlice"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "name", "Renamed vSlice"),
+					testCheckVSliceName(server, "terraform-test", "Renamed vSlice"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// TestAccVSliceResource creates, imports and renames a vSlice through the API the environment
+// names, or replays the recording of that in testdata/cassettes.
+func TestAccVSliceResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testVSliceConfig("Terraform vSlice"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "name", "Terraform vSlice"),
+					resource.TestCheckResourceAttr("stacuity_vslice.test", "moniker", "terraform-test"),
+					resource.TestCheckResourceAttrSet("stacuity_vslice.test", "id"),
+				),
+			},
+			{
+				ResourceName:            "stacuity_vslice.test",
+				ImportState:             true,
+				ImportStateId:           "terraform-test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ip_allocation_type"},
+			},
+			{
+				Config: testVSliceConfig("Renamed vSlice"),
+				Check:  resource.TestCheckResourceAttr("stacuity_vslice.test", "name", "Renamed vSlice"),
+			},
+		},
+	})
+}
+
+func testVSliceConfig(name string) string {
+	return fmt.Sprintf(`
+resource "stacuity_vslice" "test" {
+  name              = %q
+  moniker           = "terraform-test"
+  subnet_address    = "100.64.0.0/10"
+  dns_mode          = "custom"
+  dns_servers       = ["1.1.1.1", "8.8.8.8"]
+  ip_address_family = "ipv4"
+}
+`, name)
+}
+
+// testCheckVSliceName checks the name of the vSlice held by the fake API.
+func testCheckVSliceName(server *fake.Server, moniker string, name string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		vSlice, err := server.APIClient().GetVSlice(context.Background(), moniker)
+		if err != nil {
+			return err
+		}
+		if vSlice.Name != name {
+			return fmt.Errorf("vSlice %s has name %q, want %q", moniker, vSlice.Name, name)
+		}
+
+		return nil
+	}
+}
+
+func testCheckVSliceDestroy(server *fake.Server) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != "stacuity_vslice" {
+				continue
+			}
+
+			_, err := server.APIClient().GetVSlice(context.Background(), rs.Primary.ID)
+			if err == nil {
+				return fmt.Errorf("vSlice %s still exists", rs.Primary.ID)
+			}
+			if !stacuity.IsNotFound(err) {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -60,6 +60,7 @@ func (d *vSlicesDataSource) Metadata(_ context.Context, req datasource.MetadataR
 type vSlicesDataSourceModel struct {
 	VSlices []vSlicesReadModel `tfsdk:"vslices"`
 	Filter  types.Object       `tfsdk:"filter"`
+	All     types.Bool         `tfsdk:"all"`
 }
 
 // vSlicesReadModel maps vslice schema data.
@@ -180,6 +181,10 @@ func (d *vSlicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 					},
 				},
 			},
+			"all": schema.BoolAttribute{
+				Description: "Fetch every page of results rather than a single page. When set, limit is used as the page size and offset as the starting point.",
+				Optional:    true,
+			},
 			"filter": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -223,7 +228,12 @@ func (d *vSlicesDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		}
 	}
 
-	vSlices, err := d.client.GetVSlices(pagingQuery)
+	getVSlices := d.client.GetVSlices
+	if state.All.ValueBool() {
+		getVSlices = d.client.GetAllVSlices
+	}
+
+	vSlices, err := getVSlices(ctx, pagingQuery)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Stacuity vSlices",
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"stacuity.com/go_client/models"
+)
+
+func TestVSlicesDataSource(t *testing.T) {
+	server := newFakeServer(t)
+
+	for i := range 5 {
+		vSlice := models.VSliceModifyItem{Name: fmt.Sprintf("vSlice %d", i), Moniker: fmt.Sprintf("vslice-%d", i), DNSMode: "auto"}
+		if _, err := server.APIClient().CreateVSlice(context.Background(), vSlice); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A single page
+			{
+				Config: testFakeProviderConfig(server) + testVSlicesDataSourceConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.stacuity_vslices.test", "vslices.#", "2"),
+					resource.TestCheckResourceAttr("data.stacuity_vslices.test", "vslices.0.moniker", "vslice-1"),
+				),
+			},
+			// Every page from the offset, using limit as the page size
+			{
+				Config: testFakeProviderConfig(server) + testVSlicesDataSourceConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.stacuity_vslices.test", "vslices.#", "4"),
+					resource.TestCheckResourceAttr("data.stacuity_vslices.test", "vslices.0.moniker", "vslice-1"),
+					resource.TestCheckResourceAttr("data.stacuity_vslices.test", "vslices.3.moniker", "vslice-4"),
+				),
+			},
+		},
+	})
+}
+
+func testVSlicesDataSourceConfig(all bool) string {
+	return fmt.Sprintf(`
+data "stacuity_vslices" "test" {
+  all = %t
+  filter = {
+    offset = 1
+    limit  = 2
+  }
+}
+`, all)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource = &wireGuardKeyPairEphemeralResource{}
+)
+
+// NewWireGuardKeyPairEphemeralResource is a helper function to simplify the provider implementation.
+func NewWireGuardKeyPairEphemeralResource() ephemeral.EphemeralResource {
+	return &wireGuardKeyPairEphemeralResource{}
+}
+
+// wireGuardKeyPairEphemeralResource is the ephemeral resource implementation. Keys are generated
+// locally and never sent to the Stacuity API, so it needs no client.
+type wireGuardKeyPairEphemeralResource struct{}
+
+type wireGuardKeyPairEphemeralResourceModel struct {
+	PrivateKey types.String `tfsdk:"private_key"`
+	PublicKey  types.String `tfsdk:"public_key"`
+}
+
+func (r *wireGuardKeyPairEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wireguard_key_pair"
+}
+
+func (r *wireGuardKeyPairEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generates a WireGuard Curve25519 key pair locally. Being ephemeral, neither key is written to plan or state, and the keys can only be used in write-only arguments such as `remote_public_key_wo` of a `stacuity_routing_target`, in provider configuration or in other ephemeral resources. A new key pair is generated on every run, so store the private key for the customer-side peer, for example in a secrets manager, and bump `remote_public_key_wo_version` only when rotating.",
+
+		Attributes: map[string]schema.Attribute{
+			"private_key": schema.StringAttribute{
+				Description: "Base64 encoded WireGuard private key.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"public_key": schema.StringAttribute{
+				Description: "Base64 encoded WireGuard public key.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Open generates the key pair.
+func (r *wireGuardKeyPairEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	privateKey, publicKey, err := generateWireGuardKeyPair()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error generating WireGuard key pair",
+			"Could not generate WireGuard key pair, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	result := wireGuardKeyPairEphemeralResourceModel{
+		PrivateKey: types.StringValue(privateKey),
+		PublicKey:  types.StringValue(publicKey),
+	}
+
+	diags := resp.Result.Set(ctx, &result)
+	resp.Diagnostics.Append(diags...)
+}
+
+// generateWireGuardKeyPair returns a new base64 encoded private and public key, clamping the
+// private key the same way as wg genkey.
+func generateWireGuardKeyPair() (string, string, error) {
+	var key [32]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return "", "", err
+	}
+
+	key[0] &= 248
+	key[31] &= 127
+	key[31] |= 64
+
+	privateKey, err := ecdh.X25519().NewPrivateKey(key[:])
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(privateKey.Bytes()), base64.StdEncoding.EncodeToString(privateKey.PublicKey().Bytes()), nil
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	stacuity "stacuity.com/go_client"
+)
+
+const (
+	// wireGuardDefaultPort is the port used for the Stacuity endpoint when none is given.
+	wireGuardDefaultPort = 51820
+	// wireGuardPrivateKeyPlaceholder is rendered when no private key is given, so the config
+	// can be completed on the peer without the key passing through Terraform.
+	wireGuardPrivateKeyPlaceholder = "<private key>"
+	// wireGuardPublicKeyPlaceholder is rendered until the routing target has a public key.
+	wireGuardPublicKeyPlaceholder = "<public key>"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &wireGuardPeerConfigDataSource{}
+	_ datasource.DataSourceWithConfigure = &wireGuardPeerConfigDataSource{}
+)
+
+// NewWireGuardPeerConfigDataSource is a helper function to simplify the provider implementation.
+func NewWireGuardPeerConfigDataSource() datasource.DataSource {
+	return &wireGuardPeerConfigDataSource{}
+}
+
+// wireGuardPeerConfigDataSource is the data source implementation.
+type wireGuardPeerConfigDataSource struct {
+	client *stacuity.Client
+}
+
+// wireGuardPeerConfigDataSourceModel maps the data source schema data.
+type wireGuardPeerConfigDataSourceModel struct {
+	RoutingTarget       types.String `tfsdk:"routing_target"`
+	PrivateKey          types.String `tfsdk:"private_key"`
+	Address             types.String `tfsdk:"address"`
+	Endpoint            types.String `tfsdk:"endpoint"`
+	PersistentKeepalive types.Int32  `tfsdk:"persistent_keepalive"`
+	Id                  types.String `tfsdk:"id"`
+	PublicKey           types.String `tfsdk:"public_key"`
+	AllowedIPs          types.String `tfsdk:"allowed_ips"`
+	ListenPort          types.Int32  `tfsdk:"listen_port"`
+	Config              types.String `tfsdk:"config"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *wireGuardPeerConfigDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*stacuity.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *stacuity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Metadata returns the data source type name.
+func (d *wireGuardPeerConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wireguard_peer_config"
+}
+
+// Schema defines the schema for the data source.
+func (d *wireGuardPeerConfigDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders the wg-quick configuration (for example `wg0.conf`) of the customer-side peer of a WireGuard routing target.",
+		Attributes: map[string]schema.Attribute{
+			"routing_target": schema.StringAttribute{
+				Description: "Id or moniker of the WireGuard routing target.",
+				Required:    true,
+			},
+			"private_key": schema.StringAttribute{
+				Description: "Private key of the customer-side peer. It is stored in state; leave it unset to render a placeholder to fill in on the peer instead.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"address": schema.StringAttribute{
+				Description: "Tunnel address of the customer-side peer, rendered as the interface Address, e.g. 10.255.0.2/32.",
+				Optional:    true,
+			},
+			"endpoint": schema.StringAttribute{
+				Description: "Host or host:port of the Stacuity endpoint. Defaults to the public instance configuration of the routing target when that holds a host name or address, on port 51820 unless one is given.",
+				Optional:    true,
+			},
+			"persistent_keepalive": schema.Int32Attribute{
+				Description: "Seconds between keepalive packets sent to the Stacuity endpoint, useful behind NAT.",
+				Optional:    true,
+				Validators: []validator.Int32{
+					int32validator.Between(1, 65535),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "The identifier for the routing target.",
+				Computed:    true,
+			},
+			"public_key": schema.StringAttribute{
+				Description: "Public key of the Stacuity side of the tunnel.",
+				Computed:    true,
+			},
+			"allowed_ips": schema.StringAttribute{
+				Description: "Subnets routed through the tunnel to Stacuity.",
+				Computed:    true,
+			},
+			"listen_port": schema.Int32Attribute{
+				Description: "Port the customer-side peer listens on.",
+				Computed:    true,
+			},
+			"config": schema.StringAttribute{
+				Description: "The rendered wg-quick configuration.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *wireGuardPeerConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state wireGuardPeerConfigDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	routingTarget, err := d.client.GetRoutingTarget(ctx, state.RoutingTarget.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Stacuity Routing Target",
+			err.Error(),
+		)
+		return
+	}
+
+	if routingTarget.ConfigurationData == nil || routingTarget.ConfigurationData.WireGuardConfig == nil {
+		resp.Diagnostics.AddError(
+			"Not a WireGuard routing target",
+			"Routing target "+routingTarget.Moniker+" has no WireGuard configuration.",
+		)
+		return
+	}
+
+	wireGuard := routingTarget.ConfigurationData.WireGuardConfig
+	if wireGuard.LocalPublicKey == "" {
+		resp.Diagnostics.AddWarning(
+			"WireGuard public key not yet assigned",
+			"Routing target "+routingTarget.Moniker+" has no public key yet, so a placeholder is rendered in its place. Refresh once it has been provisioned.",
+		)
+	}
+
+	endpoint := state.Endpoint.ValueString()
+	if endpoint == "" && isEndpoint(routingTarget.PublicInstanceConfiguration) {
+		endpoint = routingTarget.PublicInstanceConfiguration
+	}
+	if endpoint == "" {
+		resp.Diagnostics.AddWarning(
+			"Stacuity endpoint unknown",
+			"Routing target "+routingTarget.Moniker+" does not report a public endpoint, so Endpoint is left out of the rendered configuration. Set endpoint to include it.",
+		)
+	}
+
+	peer := wireGuardPeer{
+		Comment:             "Stacuity routing target " + routingTarget.Moniker,
+		PrivateKey:          state.PrivateKey.ValueString(),
+		Address:             state.Address.ValueString(),
+		ListenPort:          wireGuard.RemotePeerPortNumber,
+		PublicKey:           wireGuard.LocalPublicKey,
+		AllowedIPs:          splitList(wireGuard.LocalSubnets),
+		Endpoint:            withDefaultPort(endpoint, wireGuardDefaultPort),
+		PersistentKeepalive: state.PersistentKeepalive.ValueInt32(),
+	}
+
+	state.Id = types.StringValue(routingTarget.Id)
+	state.PublicKey = types.StringValue(peer.PublicKey)
+	state.AllowedIPs = types.StringValue(strings.Join(peer.AllowedIPs, ", "))
+	state.ListenPort = types.Int32Value(peer.ListenPort)
+	state.Config = types.StringValue(peer.render())
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// wireGuardPeer - The settings of a wg-quick configuration with a single peer
+type wireGuardPeer struct {
+	Comment             string
+	PrivateKey          string
+	Address             string
+	ListenPort          int32
+	PublicKey           string
+	AllowedIPs          []string
+	Endpoint            string
+	PersistentKeepalive int32
+}
+
+// render returns the configuration in wg-quick format, leaving out unset optional settings.
+func (p wireGuardPeer) render() string {
+	var b strings.Builder
+
+	if p.Comment != "" {
+		fmt.Fprintf(&b, "# %s\n", p.Comment)
+	}
+
+	privateKey := p.PrivateKey
+	if privateKey == "" {
+		privateKey = wireGuardPrivateKeyPlaceholder
+	}
+
+	b.WriteString("[Interface]\n")
+	fmt.Fprintf(&b, "PrivateKey = %s\n", privateKey)
+	if p.Address != "" {
+		fmt.Fprintf(&b, "Address = %s\n", p.Address)
+	}
+	if p.ListenPort > 0 {
+		fmt.Fprintf(&b, "ListenPort = %d\n", p.ListenPort)
+	}
+
+	publicKey := p.PublicKey
+	if publicKey == "" {
+		publicKey = wireGuardPublicKeyPlaceholder
+	}
+
+	b.WriteString("\n[Peer]\n")
+	fmt.Fprintf(&b, "PublicKey = %s\n", publicKey)
+	if len(p.AllowedIPs) > 0 {
+		fmt.Fprintf(&b, "AllowedIPs = %s\n", strings.Join(p.AllowedIPs, ", "))
+	}
+	if p.Endpoint != "" {
+		fmt.Fprintf(&b, "Endpoint = %s\n", p.Endpoint)
+	}
+	if p.PersistentKeepalive > 0 {
+		fmt.Fprintf(&b, "PersistentKeepalive = %d\n", p.PersistentKeepalive)
+	}
+
+	return b.String()
+}
+
+// splitList splits a comma or whitespace separated list, such as the subnets of a routing target.
+func splitList(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// isEndpoint reports whether value looks like a host or host:port rather than structured data.
+func isEndpoint(value string) bool {
+	return value != "" && !strings.ContainsAny(value, "{}\"' \t\n=,;")
+}
+
+// withDefaultPort returns host:port, adding port to an endpoint given without one.
+func withDefaultPort(endpoint string, port int) string {
+	if endpoint == "" {
+		return ""
+	}
+
+	if _, _, err := net.SplitHostPort(endpoint); err == nil {
+		return endpoint
+	}
+
+	return net.JoinHostPort(strings.Trim(endpoint, "[]"), strconv.Itoa(port))
+}
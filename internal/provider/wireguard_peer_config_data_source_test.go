@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWireGuardPeerRender(t *testing.T) {
+	tests := map[string]struct {
+		peer wireGuardPeer
+		want string
+	}{
+		"full configuration": {
+			peer: wireGuardPeer{
+				Comment:             "Stacuity routing target wg-test",
+				PrivateKey:          "cHJpdmF0ZQ==",
+				Address:             "10.200.0.2/32",
+				ListenPort:          51821,
+				PublicKey:           "cHVibGlj",
+				AllowedIPs:          []string{"10.100.0.0/16", "fd00::/64"},
+				Endpoint:            "203.0.113.1:51820",
+				PersistentKeepalive: 25,
+			},
+			want: `# Stacuity routing target wg-test
+[Interface]
+PrivateKey = cHJpdmF0ZQ==
+Address = 10.200.0.2/32
+ListenPort = 51821
+
+[Peer]
+PublicKey = cHVibGlj
+AllowedIPs = 10.100.0.0/16, fd00::/64
+Endpoint = 203.0.113.1:51820
+PersistentKeepalive = 25
+`,
+		},
+		"optional settings unset": {
+			peer: wireGuardPeer{
+				PublicKey: "cHVibGlj",
+			},
+			want: `[Interface]
+PrivateKey = <private key>
+
+[Peer]
+PublicKey = cHVibGlj
+`,
+		},
+		"public key not yet assigned": {
+			peer: wireGuardPeer{
+				PrivateKey: "cHJpdmF0ZQ==",
+				AllowedIPs: []string{"10.100.0.0/16"},
+			},
+			want: `[Interface]
+PrivateKey = cHJpdmF0ZQ==
+
+[Peer]
+PublicKey = <public key>
+AllowedIPs = 10.100.0.0/16
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.peer.render(); got != test.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := map[string][]string{
+		"":                                  {},
+		"10.0.0.0/8":                        {"10.0.0.0/8"},
+		"10.0.0.0/8,192.168.0.0/16":         {"10.0.0.0/8", "192.168.0.0/16"},
+		" 10.0.0.0/8 ; 192.168.0.0/16 ":     {"10.0.0.0/8", "192.168.0.0/16"},
+		"10.0.0.0/8\n\tfd00::/64,, 1.1.1.1": {"10.0.0.0/8", "fd00::/64", "1.1.1.1"},
+	}
+
+	for list, want := range tests {
+		if got := splitList(list); !slices.Equal(got, want) {
+			t.Errorf("splitList(%q) = %q, want %q", list, got, want)
+		}
+	}
+}
+
+func TestIsEndpoint(t *testing.T) {
+	tests := map[string]bool{
+		"":                       false,
+		"vpn.example.com":        true,
+		"203.0.113.1:51820":      true,
+		"[2001:db8::1]:51820":    true,
+		`{"host":"203.0.113.1"}`: false,
+		"host=203.0.113.1":       false,
+		"a, b":                   false,
+	}
+
+	for value, want := range tests {
+		if got := isEndpoint(value); got != want {
+			t.Errorf("isEndpoint(%q) = %t, want %t", value, got, want)
+		}
+	}
+}
+
+func TestWithDefaultPort(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"203.0.113.1":         "203.0.113.1:51820",
+		"203.0.113.1:443":     "203.0.113.1:443",
+		"vpn.example.com":     "vpn.example.com:51820",
+		"2001:db8::1":         "[2001:db8::1]:51820",
+		"[2001:db8::1]":       "[2001:db8::1]:51820",
+		"[2001:db8::1]:51821": "[2001:db8::1]:51821",
+	}
+
+	for endpoint, want := range tests {
+		if got := withDefaultPort(endpoint, wireGuardDefaultPort); got != want {
+			t.Errorf("withDefaultPort(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
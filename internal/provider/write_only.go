@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// attributeGetter - The GetAttribute method shared by tfsdk.Config, tfsdk.Plan and tfsdk.State
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// writeOnlySecret - An attribute, usually a secret, offered alongside a write-only variant
+// (Terraform 1.11+) named <name>_wo. Terraform never keeps the write-only value in the plan or
+// state, so it is read from the configuration, and changing the <name>_wo_version attribute is
+// what triggers an update that sends a new value.
+type writeOnlySecret struct {
+	parent path.Path
+	name   string
+}
+
+func (s writeOnlySecret) value() path.Path {
+	return s.parent.AtName(s.name)
+}
+
+func (s writeOnlySecret) writeOnly() path.Path {
+	return s.parent.AtName(s.name + "_wo")
+}
+
+func (s writeOnlySecret) version() path.Path {
+	return s.parent.AtName(s.name + "_wo_version")
+}
+
+// configValue returns the write-only value set in the configuration, or null.
+func (s writeOnlySecret) configValue(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) types.String {
+	var value types.String
+	diags.Append(config.GetAttribute(ctx, s.writeOnly(), &value)...)
+	return value
+}
+
+// keepCurrent reports whether the write-only variant is in use but its version is unchanged
+// between the prior state and the plan, so the value already held by the API should be kept.
+func (s writeOnlySecret) keepCurrent(ctx context.Context, plan tfsdk.Plan, state tfsdk.State, diags *diag.Diagnostics) bool {
+	var planned, prior types.Int32
+	diags.Append(plan.GetAttribute(ctx, s.version(), &planned)...)
+	diags.Append(state.GetAttribute(ctx, s.version(), &prior)...)
+
+	return !planned.IsNull() && planned.Equal(prior)
+}
+
+// syncState fixes up a state built from an API response: the version attribute, which the API
+// does not store, is carried over from the plan or prior state, and while the write-only
+// variant is in use the value returned by the API is kept out of state.
+func (s writeOnlySecret) syncState(ctx context.Context, from attributeGetter, state *tfsdk.State, diags *diag.Diagnostics) {
+	var version types.Int32
+	diags.Append(from.GetAttribute(ctx, s.version(), &version)...)
+	if version.IsNull() || version.IsUnknown() {
+		return
+	}
+
+	diags.Append(state.SetAttribute(ctx, s.version(), version)...)
+	diags.Append(state.SetAttribute(ctx, s.value(), types.StringNull())...)
+}
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+
+package routingpolicy
+
+import (
+	"strings"
+)
+
+// Rule actions compared when looking for contradictory rules.
+const (
+	ActionForward = "forward"
+	ActionDrop    = "drop"
+)
+
+// FindingKind - The kind of problem found between two rules
+type FindingKind int
+
+const (
+	// Duplicate - The rule selects the same flows as an earlier rule and does the same with them
+	Duplicate FindingKind = iota
+	// Shadowed - An earlier rule selects every flow the rule does, so the rule never applies.
+	// With AnalyzeUnordered, the two rules select the same flows and do different things with
+	// them, so only one of them ever applies whichever comes first.
+	Shadowed
+	// Conflict - The rule and an earlier rule select some of the same flows, one to forward and
+	// the other to drop them. With Analyze, the earlier rule wins.
+	Conflict
+)
+
+// Finding - A problem between a rule and an earlier rule
+type Finding struct {
+	Kind FindingKind
+	// Index is the position of the rule in the slice passed to Analyze or AnalyzeUnordered.
+	Index int
+	// Earlier is the position of the earlier rule in the slice passed to Analyze, which takes
+	// precedence, or of the rule before Index in the slice passed to AnalyzeUnordered.
+	Earlier int
+}
+
+// Analyze compares every enabled rule with the enabled rules before it in order of precedence,
+// reporting duplicates, shadowed rules and forward/drop conflicts. A rule is only reported as
+// shadowed when a single earlier rule covers it, and rules that never apply are not compared
+// with later ones. Rules whose patterns cannot be parsed are left out and returned as errors.
+func Analyze(rules []Rule) ([]Finding, []*RuleError) {
+	type analysed struct {
+		index     int
+		rule      Rule
+		selectors []Selector
+	}
+
+	var errs []*RuleError
+	var earlier []analysed
+	var findings []Finding
+
+	for _, index := range Order(rules) {
+		rule := rules[index]
+		if !rule.Enabled {
+			continue
+		}
+
+		selector, err := Compile(rule)
+		if err != nil {
+			errs = append(errs, &RuleError{Index: index, Err: err})
+			continue
+		}
+
+		current := analysed{index: index, rule: rule, selectors: []Selector{selector}}
+		if rule.Reflexive {
+			current.selectors = append(current.selectors, selector.Reverse())
+		}
+
+		unreachable := false
+		for _, prior := range earlier {
+			if !unreachable && coversAll(prior.selectors, current.selectors) {
+				kind := Shadowed
+				if coversAll(current.selectors, prior.selectors) && sameOutcome(prior.rule, rule) {
+					kind = Duplicate
+				}
+				findings = append(findings, Finding{Kind: kind, Index: index, Earlier: prior.index})
+				unreachable = true
+				continue
+			}
+
+			if contradictory(prior.rule, rule) && overlapsAny(prior.selectors, current.selectors) {
+				findings = append(findings, Finding{Kind: Conflict, Index: index, Earlier: prior.index})
+			}
+		}
+
+		if !unreachable {
+			earlier = append(earlier, current)
+		}
+	}
+
+	return findings, errs
+}
+
+// AnalyzeUnordered compares every pair of enabled rules without regard to their precedence, for
+// rules whose order is not known. Only findings that hold in any order are reported: duplicates,
+// rules selecting the same flows with different outcomes, and forward/drop conflicts, whichever
+// of the two rules wins. Rules whose patterns cannot be parsed are left out and returned as
+// errors.
+func AnalyzeUnordered(rules []Rule) ([]Finding, []*RuleError) {
+	type analysed struct {
+		index     int
+		rule      Rule
+		selectors []Selector
+	}
+
+	var errs []*RuleError
+	var compiled []analysed
+	var findings []Finding
+
+	for index, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		selector, err := Compile(rule)
+		if err != nil {
+			errs = append(errs, &RuleError{Index: index, Err: err})
+			continue
+		}
+
+		current := analysed{index: index, rule: rule, selectors: []Selector{selector}}
+		if rule.Reflexive {
+			current.selectors = append(current.selectors, selector.Reverse())
+		}
+
+		for _, other := range compiled {
+			same := coversAll(other.selectors, current.selectors) && coversAll(current.selectors, other.selectors)
+
+			switch {
+			case same && sameOutcome(other.rule, rule):
+				findings = append(findings, Finding{Kind: Duplicate, Index: index, Earlier: other.index})
+			case same:
+				findings = append(findings, Finding{Kind: Shadowed, Index: index, Earlier: other.index})
+			case contradictory(other.rule, rule) && overlapsAny(other.selectors, current.selectors):
+				findings = append(findings, Finding{Kind: Conflict, Index: index, Earlier: other.index})
+			}
+		}
+
+		compiled = append(compiled, current)
+	}
+
+	return findings, errs
+}
+
+// Covers reports whether every flow selected by other is also selected by s.
+func (s Selector) Covers(other Selector) bool {
+	return (s.Direction == "" || s.Direction == other.Direction) &&
+		(s.Protocol == "" || s.Protocol == other.Protocol) &&
+		s.SourceIPs.Covers(other.SourceIPs) &&
+		s.DestinationIPs.Covers(other.DestinationIPs) &&
+		s.SourcePorts.Covers(other.SourcePorts) &&
+		s.DestinationPorts.Covers(other.DestinationPorts)
+}
+
+// Overlaps reports whether some flow is selected by both s and other.
+func (s Selector) Overlaps(other Selector) bool {
+	return (s.Direction == "" || other.Direction == "" || s.Direction == other.Direction) &&
+		(s.Protocol == "" || other.Protocol == "" || s.Protocol == other.Protocol) &&
+		s.SourceIPs.Overlaps(other.SourceIPs) &&
+		s.DestinationIPs.Overlaps(other.DestinationIPs) &&
+		s.SourcePorts.Overlaps(other.SourcePorts) &&
+		s.DestinationPorts.Overlaps(other.DestinationPorts)
+}
+
+// Reverse returns the selector for the return traffic of the flows s selects.
+func (s Selector) Reverse() Selector {
+	return Selector{
+		Direction:        oppositeDirection(s.Direction),
+		Protocol:         s.Protocol,
+		SourceIPs:        s.DestinationIPs,
+		DestinationIPs:   s.SourceIPs,
+		SourcePorts:      s.DestinationPorts,
+		DestinationPorts: s.SourcePorts,
+	}
+}
+
+// coversAll reports whether each of others is covered by one of selectors.
+func coversAll(selectors []Selector, others []Selector) bool {
+	for _, other := range others {
+		covered := false
+		for _, selector := range selectors {
+			if selector.Covers(other) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// overlapsAny reports whether any of selectors overlaps any of others.
+func overlapsAny(selectors []Selector, others []Selector) bool {
+	for _, selector := range selectors {
+		for _, other := range others {
+			if selector.Overlaps(other) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sameOutcome reports whether two rules do the same with the flows they select.
+func sameOutcome(a Rule, b Rule) bool {
+	return strings.EqualFold(a.RuleAction, b.RuleAction) && strings.EqualFold(a.RoutingTarget, b.RoutingTarget)
+}
+
+// contradictory reports whether one rule forwards and the other drops.
+func contradictory(a Rule, b Rule) bool {
+	actionA, actionB := strings.ToLower(a.RuleAction), strings.ToLower(b.RuleAction)
+	return actionA == ActionForward && actionB == ActionDrop || actionA == ActionDrop && actionB == ActionForward
+}
@@ -0,0 +1,223 @@
+// Copyright (c) HashiCorp, Inc.
+
+package routingpolicy
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// testRule returns an enabled uplink rule with the given action and destination patterns.
+func testRule(description, action, destinationIPs, destinationPorts string) Rule {
+	return Rule{
+		Description:            description,
+		RuleAction:             action,
+		RuleDirection:          DirectionUplink,
+		TransportProtocol:      "tcp",
+		DestinationIpPattern:   destinationIPs,
+		DestinationPortPattern: destinationPorts,
+		Enabled:                true,
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	web := testRule("web", ActionForward, "10.0.0.0/24", "443")
+	webAgain := testRule("web again", ActionForward, "10.0.0.0/24", "443")
+	webHost := testRule("web host", ActionForward, "10.0.0.5", "443")
+	dropSubnet := testRule("drop subnet", ActionDrop, "10.0.0.0/16", "*")
+	dropOther := testRule("drop other", ActionDrop, "192.168.0.0/16", "*")
+
+	disabled := webAgain
+	disabled.Enabled = false
+
+	invalid := testRule("invalid", ActionDrop, "10.0.0.300", "*")
+
+	reflexive := testRule("reflexive", ActionForward, "10.0.0.0/24", "443")
+	reflexive.Reflexive = true
+	returnTraffic := Rule{
+		Description: "return", RuleAction: ActionDrop, RuleDirection: DirectionDownlink, TransportProtocol: "tcp",
+		SourceIpPattern: "10.0.0.1", SourcePortPattern: "443", Enabled: true,
+	}
+
+	tests := map[string]struct {
+		rules    []Rule
+		want     []Finding
+		wantErrs []int
+	}{
+		"no findings": {
+			rules: []Rule{web, dropOther},
+		},
+		"duplicate": {
+			rules: []Rule{web, webAgain},
+			want:  []Finding{{Kind: Duplicate, Index: 1, Earlier: 0}},
+		},
+		"shadowed by a broader rule": {
+			rules: []Rule{web, webHost},
+			want:  []Finding{{Kind: Shadowed, Index: 1, Earlier: 0}},
+		},
+		"broader rule after a narrower one": {
+			rules: []Rule{webHost, web},
+		},
+		"shadowed with a different action": {
+			rules: []Rule{dropSubnet, web},
+			want:  []Finding{{Kind: Shadowed, Index: 1, Earlier: 0}},
+		},
+		"conflict": {
+			rules: []Rule{web, dropSubnet},
+			want:  []Finding{{Kind: Conflict, Index: 1, Earlier: 0}},
+		},
+		"shadowed rules are not compared with later ones": {
+			rules: []Rule{web, webHost, dropSubnet},
+			want: []Finding{
+				{Kind: Shadowed, Index: 1, Earlier: 0},
+				{Kind: Conflict, Index: 2, Earlier: 0},
+			},
+		},
+		"precedence orders the rules": {
+			rules: func() []Rule {
+				first, second := webHost, web
+				first.Precedence, second.Precedence = 2, 1
+				return []Rule{first, second}
+			}(),
+			want: []Finding{{Kind: Shadowed, Index: 0, Earlier: 1}},
+		},
+		"disabled rules are skipped": {
+			rules: []Rule{web, disabled},
+		},
+		"reflexive rule covers return traffic": {
+			rules: []Rule{reflexive, returnTraffic},
+			want:  []Finding{{Kind: Shadowed, Index: 1, Earlier: 0}},
+		},
+		"return traffic before a reflexive rule": {
+			rules: []Rule{returnTraffic, reflexive},
+			want:  []Finding{{Kind: Conflict, Index: 1, Earlier: 0}},
+		},
+		"return traffic without reflexive": {
+			rules: []Rule{web, returnTraffic},
+		},
+		"invalid pattern": {
+			rules:    []Rule{web, invalid, webAgain},
+			want:     []Finding{{Kind: Duplicate, Index: 2, Earlier: 0}},
+			wantErrs: []int{1},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			findings, errs := Analyze(test.rules)
+
+			if !reflect.DeepEqual(findings, test.want) {
+				t.Errorf("got findings %+v, want %+v", findings, test.want)
+			}
+			if got := ruleErrorIndexes(errs); !slices.Equal(got, test.wantErrs) {
+				t.Errorf("got errors for rules %v, want %v", got, test.wantErrs)
+			}
+		})
+	}
+}
+
+func TestAnalyzeUnordered(t *testing.T) {
+	web := testRule("web", ActionForward, "10.0.0.0/24", "443")
+	webAgain := testRule("web again", ActionForward, "10.0.0.0/24", "443")
+	webHost := testRule("web host", ActionForward, "10.0.0.5", "443")
+	dropSubnet := testRule("drop subnet", ActionDrop, "10.0.0.0/16", "*")
+	dropOther := testRule("drop other", ActionDrop, "192.168.0.0/16", "*")
+
+	otherTarget := webAgain
+	otherTarget.RoutingTarget = "elsewhere"
+
+	dropWeb := testRule("drop web", ActionDrop, "10.0.0.0/24", "443")
+
+	invalid := testRule("invalid", ActionDrop, "10.0.0.0/33", "*")
+
+	tests := map[string]struct {
+		rules    []Rule
+		want     []Finding
+		wantErrs []int
+	}{
+		"no findings": {
+			rules: []Rule{web, dropOther},
+		},
+		"duplicate": {
+			rules: []Rule{web, webAgain},
+			want:  []Finding{{Kind: Duplicate, Index: 1, Earlier: 0}},
+		},
+		"same flows to another target": {
+			rules: []Rule{web, otherTarget},
+			want:  []Finding{{Kind: Shadowed, Index: 1, Earlier: 0}},
+		},
+		"same flows forwarded and dropped": {
+			rules: []Rule{web, dropWeb},
+			want:  []Finding{{Kind: Shadowed, Index: 1, Earlier: 0}},
+		},
+		"covered rule with the same action": {
+			rules: []Rule{web, webHost},
+		},
+		"covered rule with the opposite action": {
+			rules: []Rule{dropSubnet, web},
+			want:  []Finding{{Kind: Conflict, Index: 1, Earlier: 0}},
+		},
+		"every conflicting pair": {
+			rules: []Rule{web, webHost, dropSubnet},
+			want: []Finding{
+				{Kind: Conflict, Index: 2, Earlier: 0},
+				{Kind: Conflict, Index: 2, Earlier: 1},
+			},
+		},
+		"invalid pattern": {
+			rules:    []Rule{invalid, web, dropSubnet},
+			want:     []Finding{{Kind: Conflict, Index: 2, Earlier: 1}},
+			wantErrs: []int{0},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			findings, errs := AnalyzeUnordered(test.rules)
+
+			if !reflect.DeepEqual(findings, test.want) {
+				t.Errorf("got findings %+v, want %+v", findings, test.want)
+			}
+			if got := ruleErrorIndexes(errs); !slices.Equal(got, test.wantErrs) {
+				t.Errorf("got errors for rules %v, want %v", got, test.wantErrs)
+			}
+
+			// Reversing the rules, or giving them precedence, finds the same pairs.
+			reversed := slices.Clone(test.rules)
+			slices.Reverse(reversed)
+			for i := range reversed {
+				reversed[i].Precedence = int32(i)
+			}
+			reversedFindings, _ := AnalyzeUnordered(reversed)
+			if got, want := findingPairs(reversedFindings, len(reversed)), findingPairs(findings, 0); !reflect.DeepEqual(got, want) {
+				t.Errorf("reversed rules found %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func ruleErrorIndexes(errs []*RuleError) []int {
+	var indexes []int
+	for _, err := range errs {
+		indexes = append(indexes, err.Index)
+	}
+
+	return indexes
+}
+
+// findingPairs returns the kind and rule positions of findings, in a form that does not depend
+// on which of the two rules came first. A non-zero count maps positions in a reversed slice
+// back to the original ones.
+func findingPairs(findings []Finding, count int) map[[2]int]FindingKind {
+	pairs := map[[2]int]FindingKind{}
+	for _, finding := range findings {
+		a, b := finding.Index, finding.Earlier
+		if count > 0 {
+			a, b = count-1-a, count-1-b
+		}
+		pairs[[2]int{min(a, b), max(a, b)}] = finding.Kind
+	}
+
+	return pairs
+}
@@ -0,0 +1,337 @@
+// Copyright (c) HashiCorp, Inc.
+
+package routingpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IPSet - The addresses matched by an IP pattern, as a list of inclusive ranges
+type IPSet struct {
+	ranges []ipRange
+}
+
+type ipRange struct {
+	from netip.Addr
+	to   netip.Addr
+}
+
+// AnyIP - The set matching every IPv4 and IPv6 address, as an unset pattern does
+var AnyIP = IPSet{ranges: []ipRange{
+	{from: netip.IPv4Unspecified(), to: netip.AddrFrom4([4]byte{255, 255, 255, 255})},
+	{from: netip.IPv6Unspecified(), to: netip.AddrFrom16([16]byte{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255})},
+}}
+
+// ParseIPPattern parses an IP pattern. A pattern is a comma or space separated list of
+// addresses (10.0.0.1), prefixes (10.0.0.0/8), ranges (10.0.0.1-10.0.0.9) and IPv4 addresses
+// with trailing wildcard octets (10.0.*.*). An empty pattern, * or any matches every address.
+func ParseIPPattern(pattern string) (IPSet, error) {
+	items := splitPattern(pattern)
+	if isWildcard(items) {
+		return AnyIP, nil
+	}
+
+	var set IPSet
+	for _, item := range items {
+		r, err := parseIPItem(item)
+		if err != nil {
+			return IPSet{}, fmt.Errorf("invalid IP pattern %q: %w", item, err)
+		}
+		set.ranges = append(set.ranges, r)
+	}
+
+	return set, nil
+}
+
+func parseIPItem(item string) (ipRange, error) {
+	switch {
+	case strings.Contains(item, "/"):
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return ipRange{}, err
+		}
+		prefix = prefix.Masked()
+		return ipRange{from: prefix.Addr().Unmap(), to: lastAddr(prefix).Unmap()}, nil
+
+	case strings.Contains(item, "-"):
+		from, to, _ := strings.Cut(item, "-")
+		fromAddr, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return ipRange{}, err
+		}
+		toAddr, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil {
+			return ipRange{}, err
+		}
+		fromAddr, toAddr = fromAddr.Unmap(), toAddr.Unmap()
+		if fromAddr.Is4() != toAddr.Is4() || toAddr.Less(fromAddr) {
+			return ipRange{}, fmt.Errorf("range must run from low to high within one address family")
+		}
+		return ipRange{from: fromAddr, to: toAddr}, nil
+
+	case strings.Contains(item, "*"):
+		return parseIPWildcard(item)
+	}
+
+	addr, err := netip.ParseAddr(item)
+	if err != nil {
+		return ipRange{}, err
+	}
+	addr = addr.Unmap()
+
+	return ipRange{from: addr, to: addr}, nil
+}
+
+// parseIPWildcard parses an IPv4 address whose trailing octets are *.
+func parseIPWildcard(item string) (ipRange, error) {
+	octets := strings.Split(item, ".")
+	if len(octets) != 4 {
+		return ipRange{}, fmt.Errorf("wildcards are only supported in IPv4 addresses")
+	}
+
+	var from, to [4]byte
+	wildcard := false
+	for i, octet := range octets {
+		if octet == "*" {
+			wildcard = true
+			from[i], to[i] = 0, 255
+			continue
+		}
+		if wildcard {
+			return ipRange{}, fmt.Errorf("only trailing octets may be wildcards")
+		}
+
+		value, err := strconv.ParseUint(octet, 10, 8)
+		if err != nil {
+			return ipRange{}, fmt.Errorf("invalid octet %q", octet)
+		}
+		from[i], to[i] = byte(value), byte(value)
+	}
+
+	return ipRange{from: netip.AddrFrom4(from), to: netip.AddrFrom4(to)}, nil
+}
+
+// lastAddr returns the highest address in prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(addr)*8; bit++ {
+		addr[bit/8] |= 0x80 >> (bit % 8)
+	}
+
+	last, _ := netip.AddrFromSlice(addr)
+	return last
+}
+
+// Contains reports whether addr is in the set.
+func (s IPSet) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, r := range s.ranges {
+		if r.from.Is4() == addr.Is4() && !addr.Less(r.from) && !r.to.Less(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Covers reports whether every address in other is also in s.
+func (s IPSet) Covers(other IPSet) bool {
+	merged := s.merged()
+	for _, r := range other.ranges {
+		covered := false
+		for _, m := range merged {
+			if m.from.Is4() == r.from.Is4() && !r.from.Less(m.from) && !m.to.Less(r.to) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Overlaps reports whether some address is in both s and other.
+func (s IPSet) Overlaps(other IPSet) bool {
+	for _, a := range s.ranges {
+		for _, b := range other.ranges {
+			if a.from.Is4() == b.from.Is4() && !b.to.Less(a.from) && !a.to.Less(b.from) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// merged returns the ranges of s sorted, with overlapping and adjacent ranges joined.
+func (s IPSet) merged() []ipRange {
+	ranges := append([]ipRange(nil), s.ranges...)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].from.Less(ranges[j].from)
+	})
+
+	var merged []ipRange
+	for _, r := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && merged[last].from.Is4() == r.from.Is4() && !merged[last].to.Next().Less(r.from) {
+			if merged[last].to.Less(r.to) {
+				merged[last].to = r.to
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// PortSet - The ports matched by a port pattern, as a list of inclusive ranges
+type PortSet struct {
+	ranges []portRange
+}
+
+type portRange struct {
+	from uint16
+	to   uint16
+}
+
+// AnyPort - The set matching every port, and flows without ports, as an unset pattern does
+var AnyPort = PortSet{ranges: []portRange{{from: 0, to: 65535}}}
+
+// ParsePortPattern parses a port pattern. A pattern is a comma or space separated list of
+// ports (443) and ranges (8000-8080 or 8000:8080). An empty pattern, * or any matches every port.
+func ParsePortPattern(pattern string) (PortSet, error) {
+	items := splitPattern(pattern)
+	if isWildcard(items) {
+		return AnyPort, nil
+	}
+
+	var set PortSet
+	for _, item := range items {
+		r, err := parsePortItem(item)
+		if err != nil {
+			return PortSet{}, fmt.Errorf("invalid port pattern %q: %w", item, err)
+		}
+		set.ranges = append(set.ranges, r)
+	}
+
+	return set, nil
+}
+
+func parsePortItem(item string) (portRange, error) {
+	from, to, isRange := strings.Cut(item, "-")
+	if !isRange {
+		from, to, isRange = strings.Cut(item, ":")
+	}
+	if !isRange {
+		to = from
+	}
+
+	fromPort, err := strconv.ParseUint(strings.TrimSpace(from), 10, 16)
+	if err != nil {
+		return portRange{}, fmt.Errorf("invalid port %q", from)
+	}
+	toPort, err := strconv.ParseUint(strings.TrimSpace(to), 10, 16)
+	if err != nil {
+		return portRange{}, fmt.Errorf("invalid port %q", to)
+	}
+	if toPort < fromPort {
+		return portRange{}, fmt.Errorf("range must run from low to high")
+	}
+
+	return portRange{from: uint16(fromPort), to: uint16(toPort)}, nil
+}
+
+// Contains reports whether port is in the set. Port 0 stands for a flow without ports.
+func (s PortSet) Contains(port uint16) bool {
+	for _, r := range s.ranges {
+		if port >= r.from && port <= r.to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Covers reports whether every port in other is also in s.
+func (s PortSet) Covers(other PortSet) bool {
+	merged := s.merged()
+	for _, r := range other.ranges {
+		covered := false
+		for _, m := range merged {
+			if r.from >= m.from && r.to <= m.to {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Overlaps reports whether some port is in both s and other.
+func (s PortSet) Overlaps(other PortSet) bool {
+	for _, a := range s.ranges {
+		for _, b := range other.ranges {
+			if a.from <= b.to && b.from <= a.to {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// merged returns the ranges of s sorted, with overlapping and adjacent ranges joined.
+func (s PortSet) merged() []portRange {
+	ranges := append([]portRange(nil), s.ranges...)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].from < ranges[j].from
+	})
+
+	var merged []portRange
+	for _, r := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && int(r.from) <= int(merged[last].to)+1 {
+			if r.to > merged[last].to {
+				merged[last].to = r.to
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// splitPattern splits a pattern into its comma or space separated items.
+func splitPattern(pattern string) []string {
+	return strings.FieldsFunc(pattern, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// isWildcard reports whether the items of a pattern match anything.
+func isWildcard(items []string) bool {
+	if len(items) == 0 {
+		return true
+	}
+
+	for _, item := range items {
+		if item == "*" || strings.EqualFold(item, "any") {
+			return true
+		}
+	}
+
+	return false
+}
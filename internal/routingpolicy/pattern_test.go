@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+
+package routingpolicy
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestParseIPPattern(t *testing.T) {
+	tests := map[string]struct {
+		pattern  string
+		contains []string
+		excludes []string
+		wantErr  bool
+	}{
+		"empty matches every address": {
+			pattern:  "",
+			contains: []string{"0.0.0.0", "255.255.255.255", "::", "2001:db8::1"},
+		},
+		"star": {
+			pattern:  "*",
+			contains: []string{"10.0.0.1", "::1"},
+		},
+		"any among other items": {
+			pattern:  "10.0.0.1, ANY",
+			contains: []string{"192.168.0.1", "fe80::1"},
+		},
+		"address": {
+			pattern:  "10.0.0.1",
+			contains: []string{"10.0.0.1", "::ffff:10.0.0.1"},
+			excludes: []string{"10.0.0.2", "::a00:1"},
+		},
+		"prefix": {
+			pattern:  "10.0.0.0/24",
+			contains: []string{"10.0.0.0", "10.0.0.255"},
+			excludes: []string{"9.255.255.255", "10.0.1.0"},
+		},
+		"prefix with host bits": {
+			pattern:  "10.0.0.5/24",
+			contains: []string{"10.0.0.0", "10.0.0.255"},
+		},
+		"whole IPv4 prefix": {
+			pattern:  "0.0.0.0/0",
+			contains: []string{"0.0.0.0", "255.255.255.255"},
+			excludes: []string{"::"},
+		},
+		"range": {
+			pattern:  "10.0.0.1-10.0.0.9",
+			contains: []string{"10.0.0.1", "10.0.0.9"},
+			excludes: []string{"10.0.0.0", "10.0.0.10"},
+		},
+		"wildcard octets": {
+			pattern:  "10.0.*.*",
+			contains: []string{"10.0.0.0", "10.0.255.255"},
+			excludes: []string{"10.1.0.0"},
+		},
+		"list separated by commas and spaces": {
+			pattern:  "10.0.0.1,10.0.0.3 10.0.0.5",
+			contains: []string{"10.0.0.1", "10.0.0.3", "10.0.0.5"},
+			excludes: []string{"10.0.0.2", "10.0.0.4"},
+		},
+		"IPv6 address": {
+			pattern:  "2001:db8::1",
+			contains: []string{"2001:db8::1"},
+			excludes: []string{"2001:db8::2", "10.0.0.1"},
+		},
+		"IPv6 prefix": {
+			pattern:  "2001:db8::/32",
+			contains: []string{"2001:db8::", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff"},
+			excludes: []string{"2001:db9::", "32.1.13.184"},
+		},
+		"IPv6 range": {
+			pattern:  "2001:db8::1-2001:db8::ff",
+			contains: []string{"2001:db8::80"},
+			excludes: []string{"2001:db8::100"},
+		},
+		"IPv4-mapped IPv6 address": {
+			pattern:  "::ffff:10.0.0.1",
+			contains: []string{"10.0.0.1"},
+		},
+		"octet out of range":          {pattern: "10.0.0.256", wantErr: true},
+		"prefix too long":             {pattern: "10.0.0.0/33", wantErr: true},
+		"IPv6 prefix too long":        {pattern: "2001:db8::/129", wantErr: true},
+		"range from high to low":      {pattern: "10.0.0.9-10.0.0.1", wantErr: true},
+		"range across families":       {pattern: "10.0.0.1-2001:db8::1", wantErr: true},
+		"range without an end":        {pattern: "10.0.0.1-", wantErr: true},
+		"wildcard before an octet":    {pattern: "10.*.0.1", wantErr: true},
+		"wildcard in IPv6":            {pattern: "2001:db8::*", wantErr: true},
+		"wildcard with too few parts": {pattern: "10.0.*", wantErr: true},
+		"hostname":                    {pattern: "example.com", wantErr: true},
+		"one bad item in a list":      {pattern: "10.0.0.1, 10.0.0", wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set, err := ParseIPPattern(test.pattern)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseIPPattern(%q) succeeded, want an error", test.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIPPattern(%q): %s", test.pattern, err)
+			}
+
+			for _, addr := range test.contains {
+				if !set.Contains(netip.MustParseAddr(addr)) {
+					t.Errorf("%q does not contain %s", test.pattern, addr)
+				}
+			}
+			for _, addr := range test.excludes {
+				if set.Contains(netip.MustParseAddr(addr)) {
+					t.Errorf("%q contains %s", test.pattern, addr)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePortPattern(t *testing.T) {
+	tests := map[string]struct {
+		pattern  string
+		contains []uint16
+		excludes []uint16
+		wantErr  bool
+	}{
+		"empty matches every port": {
+			pattern:  "",
+			contains: []uint16{0, 1, 65535},
+		},
+		"any": {
+			pattern:  "any",
+			contains: []uint16{0, 443},
+		},
+		"port": {
+			pattern:  "443",
+			contains: []uint16{443},
+			excludes: []uint16{0, 442, 444},
+		},
+		"lowest port": {
+			pattern:  "0",
+			contains: []uint16{0},
+			excludes: []uint16{1},
+		},
+		"highest port": {
+			pattern:  "65535",
+			contains: []uint16{65535},
+			excludes: []uint16{65534},
+		},
+		"range with a dash": {
+			pattern:  "8000-8080",
+			contains: []uint16{8000, 8080},
+			excludes: []uint16{7999, 8081},
+		},
+		"range with a colon": {
+			pattern:  "8000:8080",
+			contains: []uint16{8000, 8080},
+			excludes: []uint16{7999, 8081},
+		},
+		"full range": {
+			pattern:  "0-65535",
+			contains: []uint16{0, 65535},
+		},
+		"single port range": {
+			pattern:  "53-53",
+			contains: []uint16{53},
+			excludes: []uint16{52, 54},
+		},
+		"list": {
+			pattern:  "80, 443 8000-8001",
+			contains: []uint16{80, 443, 8001},
+			excludes: []uint16{81, 8002},
+		},
+		"spaces around a comma": {
+			pattern:  "80 ,81",
+			contains: []uint16{80, 81},
+		},
+		"above the highest port":  {pattern: "65536", wantErr: true},
+		"range above the highest": {pattern: "65000-65536", wantErr: true},
+		"negative":                {pattern: "-1", wantErr: true},
+		"range from high to low":  {pattern: "8080-8000", wantErr: true},
+		"range without an end":    {pattern: "8000-", wantErr: true},
+		"name":                    {pattern: "https", wantErr: true},
+		"one bad item in a list":  {pattern: "80, x", wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set, err := ParsePortPattern(test.pattern)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePortPattern(%q) succeeded, want an error", test.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePortPattern(%q): %s", test.pattern, err)
+			}
+
+			for _, port := range test.contains {
+				if !set.Contains(port) {
+					t.Errorf("%q does not contain %d", test.pattern, port)
+				}
+			}
+			for _, port := range test.excludes {
+				if set.Contains(port) {
+					t.Errorf("%q contains %d", test.pattern, port)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package routingpolicy evaluates Stacuity routing policy rules against a traffic flow, to
+// answer which rule, action and routing target apply to a packet without sending it.
+package routingpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// Rule directions, as used by the rule_direction attribute and Flow.Direction.
+const (
+	DirectionUplink   = "uplink"
+	DirectionDownlink = "downlink"
+)
+
+// Rule - A routing policy rule, with the fields of a routing_policy_rules entry
+type Rule struct {
+	Description            string
+	RuleAction             string
+	RuleDirection          string
+	SourceIpPattern        string
+	DestinationIpPattern   string
+	TransportProtocol      string
+	SourcePortPattern      string
+	DestinationPortPattern string
+	RoutingTarget          string
+	Reflexive              bool
+	Enabled                bool
+	// Precedence orders the rules, lowest first. Rules with equal precedence keep their order.
+	Precedence int32
+}
+
+// Flow - The packet to evaluate the rules against
+type Flow struct {
+	// Direction is DirectionUplink, from the device, or DirectionDownlink, towards it.
+	Direction     string
+	Protocol      string
+	SourceIP      netip.Addr
+	DestinationIP netip.Addr
+	// SourcePort and DestinationPort are 0 for protocols without ports, such as ICMP.
+	SourcePort      uint16
+	DestinationPort uint16
+}
+
+// Match - The rule a flow matched
+type Match struct {
+	// Index is the position of the rule in the slice passed to Evaluate.
+	Index int
+	Rule  Rule
+	// Reflected is set when the flow matched as return traffic of a reflexive rule.
+	Reflected bool
+}
+
+// RuleError - A rule whose patterns could not be parsed
+type RuleError struct {
+	Index int
+	Err   error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %d: %s", e.Index, e.Err)
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// Selector - The parsed patterns of a rule, which together select the flows the rule applies to
+type Selector struct {
+	// Direction is empty when the rule applies in both directions.
+	Direction string
+	// Protocol is empty when the rule applies to every protocol.
+	Protocol         string
+	SourceIPs        IPSet
+	DestinationIPs   IPSet
+	SourcePorts      PortSet
+	DestinationPorts PortSet
+}
+
+// Compile parses the direction, protocol and patterns of rule.
+func Compile(rule Rule) (Selector, error) {
+	var selector Selector
+	var err error
+
+	selector.Direction = normalizeDirection(rule.RuleDirection)
+	selector.Protocol = normalizeProtocol(rule.TransportProtocol)
+
+	if selector.SourceIPs, err = ParseIPPattern(rule.SourceIpPattern); err != nil {
+		return Selector{}, fmt.Errorf("source_ip_pattern: %w", err)
+	}
+	if selector.DestinationIPs, err = ParseIPPattern(rule.DestinationIpPattern); err != nil {
+		return Selector{}, fmt.Errorf("destination_ip_pattern: %w", err)
+	}
+	if selector.SourcePorts, err = ParsePortPattern(rule.SourcePortPattern); err != nil {
+		return Selector{}, fmt.Errorf("source_port_pattern: %w", err)
+	}
+	if selector.DestinationPorts, err = ParsePortPattern(rule.DestinationPortPattern); err != nil {
+		return Selector{}, fmt.Errorf("destination_port_pattern: %w", err)
+	}
+
+	return selector, nil
+}
+
+// Matches reports whether flow is selected.
+func (s Selector) Matches(flow Flow) bool {
+	if s.Direction != "" && s.Direction != normalizeDirection(flow.Direction) {
+		return false
+	}
+	if s.Protocol != "" && s.Protocol != normalizeProtocol(flow.Protocol) {
+		return false
+	}
+
+	return s.SourceIPs.Contains(flow.SourceIP) &&
+		s.DestinationIPs.Contains(flow.DestinationIP) &&
+		s.SourcePorts.Contains(flow.SourcePort) &&
+		s.DestinationPorts.Contains(flow.DestinationPort)
+}
+
+// Reverse returns the return traffic of flow: the opposite direction with source and
+// destination swapped.
+func (f Flow) Reverse() Flow {
+	return Flow{
+		Direction:       oppositeDirection(normalizeDirection(f.Direction)),
+		Protocol:        f.Protocol,
+		SourceIP:        f.DestinationIP,
+		DestinationIP:   f.SourceIP,
+		SourcePort:      f.DestinationPort,
+		DestinationPort: f.SourcePort,
+	}
+}
+
+// Evaluate returns the first enabled rule, in order of precedence, that matches flow, or false
+// when no rule does. A reflexive rule also matches the return traffic of the flows it selects.
+func Evaluate(rules []Rule, flow Flow) (Match, bool, error) {
+	switch normalizeDirection(flow.Direction) {
+	case DirectionUplink, DirectionDownlink:
+	default:
+		return Match{}, false, fmt.Errorf("flow direction must be %s or %s, got: %q", DirectionUplink, DirectionDownlink, flow.Direction)
+	}
+	if !flow.SourceIP.IsValid() || !flow.DestinationIP.IsValid() {
+		return Match{}, false, fmt.Errorf("flow source and destination IP are required")
+	}
+
+	for _, index := range Order(rules) {
+		rule := rules[index]
+		if !rule.Enabled {
+			continue
+		}
+
+		selector, err := Compile(rule)
+		if err != nil {
+			return Match{}, false, &RuleError{Index: index, Err: err}
+		}
+
+		if selector.Matches(flow) {
+			return Match{Index: index, Rule: rule}, true, nil
+		}
+		if rule.Reflexive && selector.Matches(flow.Reverse()) {
+			return Match{Index: index, Rule: rule, Reflected: true}, true, nil
+		}
+	}
+
+	return Match{}, false, nil
+}
+
+// Order returns the indexes of rules in the order they are evaluated.
+func Order(rules []Rule) []int {
+	order := make([]int, len(rules))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return rules[order[a]].Precedence < rules[order[b]].Precedence
+	})
+
+	return order
+}
+
+// normalizeDirection returns direction in lower case, or empty for a rule applying both ways.
+func normalizeDirection(direction string) string {
+	direction = strings.ToLower(strings.TrimSpace(direction))
+	switch direction {
+	case "any", "both", "bidirectional", "*":
+		return ""
+	}
+
+	return direction
+}
+
+// oppositeDirection returns the direction of return traffic, leaving both ways unchanged.
+func oppositeDirection(direction string) string {
+	switch direction {
+	case DirectionUplink:
+		return DirectionDownlink
+	case DirectionDownlink:
+		return DirectionUplink
+	}
+
+	return direction
+}
+
+// normalizeProtocol returns protocol in lower case, or empty for a rule applying to all.
+func normalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	switch protocol {
+	case "any", "all", "*":
+		return ""
+	}
+
+	return protocol
+}
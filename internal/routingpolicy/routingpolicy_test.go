@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+
+package routingpolicy
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	web := testRule("web", ActionForward, "10.0.0.0/24", "443")
+	web.RoutingTarget = "internet"
+	dropAll := Rule{Description: "drop all", RuleAction: ActionDrop, Enabled: true}
+
+	reflexive := web
+	reflexive.Description = "reflexive web"
+	reflexive.Reflexive = true
+
+	disabled := web
+	disabled.Description = "disabled"
+	disabled.Enabled = false
+
+	bothWays := Rule{Description: "both ways", RuleAction: ActionForward, RuleDirection: "any", DestinationIpPattern: "10.0.0.1", Enabled: true}
+	icmp := Rule{Description: "icmp", RuleAction: ActionForward, TransportProtocol: "ICMP", Enabled: true}
+	invalid := testRule("invalid", ActionDrop, "10.0.0.0/33", "*")
+
+	first, second := web, dropAll
+	first.Precedence, second.Precedence = 20, 10
+
+	uplink := Flow{
+		Direction:       DirectionUplink,
+		Protocol:        "tcp",
+		SourceIP:        netip.MustParseAddr("100.64.0.1"),
+		DestinationIP:   netip.MustParseAddr("10.0.0.1"),
+		SourcePort:      50000,
+		DestinationPort: 443,
+	}
+	returnTraffic := uplink.Reverse()
+
+	tests := map[string]struct {
+		rules         []Rule
+		flow          Flow
+		wantIndex     int
+		wantMatched   bool
+		wantReflected bool
+		wantErr       bool
+	}{
+		"first matching rule in list order": {
+			rules:       []Rule{dropAll, web},
+			flow:        uplink,
+			wantIndex:   0,
+			wantMatched: true,
+		},
+		"rule after one that does not match": {
+			rules:       []Rule{icmp, web, dropAll},
+			flow:        uplink,
+			wantIndex:   1,
+			wantMatched: true,
+		},
+		"precedence before list order": {
+			rules:       []Rule{first, second},
+			flow:        uplink,
+			wantIndex:   1,
+			wantMatched: true,
+		},
+		"disabled rule is skipped": {
+			rules:       []Rule{disabled, dropAll},
+			flow:        uplink,
+			wantIndex:   1,
+			wantMatched: true,
+		},
+		"no rule matches": {
+			rules: []Rule{web},
+			flow:  Flow{Direction: DirectionUplink, Protocol: "tcp", SourceIP: uplink.SourceIP, DestinationIP: netip.MustParseAddr("10.0.1.1"), DestinationPort: 443},
+		},
+		"no rules": {
+			flow: uplink,
+		},
+		"reflexive rule matches return traffic": {
+			rules:         []Rule{reflexive, dropAll},
+			flow:          returnTraffic,
+			wantIndex:     0,
+			wantMatched:   true,
+			wantReflected: true,
+		},
+		"reflexive rule matches forward traffic": {
+			rules:       []Rule{reflexive},
+			flow:        uplink,
+			wantIndex:   0,
+			wantMatched: true,
+		},
+		"rule without reflexive does not match return traffic": {
+			rules:       []Rule{web, dropAll},
+			flow:        returnTraffic,
+			wantIndex:   1,
+			wantMatched: true,
+		},
+		"rule applying both ways": {
+			rules:       []Rule{bothWays},
+			flow:        Flow{Direction: "Downlink", SourceIP: uplink.SourceIP, DestinationIP: uplink.DestinationIP},
+			wantIndex:   0,
+			wantMatched: true,
+		},
+		"flow without ports matches unset port patterns": {
+			rules:       []Rule{web, icmp},
+			flow:        Flow{Direction: DirectionUplink, Protocol: "icmp", SourceIP: uplink.SourceIP, DestinationIP: uplink.DestinationIP},
+			wantIndex:   1,
+			wantMatched: true,
+		},
+		"IPv6 flow": {
+			rules:       []Rule{testRule("v6", ActionForward, "2001:db8::/32", ""), dropAll},
+			flow:        Flow{Direction: DirectionUplink, Protocol: "udp", SourceIP: netip.MustParseAddr("2001:db8:1::1"), DestinationIP: netip.MustParseAddr("2001:db8::53")},
+			wantIndex:   1,
+			wantMatched: true,
+		},
+		"invalid rule before a match": {
+			rules:   []Rule{invalid, web},
+			flow:    uplink,
+			wantErr: true,
+		},
+		"invalid rule after a match": {
+			rules:       []Rule{web, invalid},
+			flow:        uplink,
+			wantIndex:   0,
+			wantMatched: true,
+		},
+		"invalid direction": {
+			rules:   []Rule{web},
+			flow:    Flow{Direction: "sideways", SourceIP: uplink.SourceIP, DestinationIP: uplink.DestinationIP},
+			wantErr: true,
+		},
+		"direction applying both ways": {
+			rules:   []Rule{web},
+			flow:    Flow{Direction: "any", SourceIP: uplink.SourceIP, DestinationIP: uplink.DestinationIP},
+			wantErr: true,
+		},
+		"missing address": {
+			rules:   []Rule{web},
+			flow:    Flow{Direction: DirectionUplink, SourceIP: uplink.SourceIP},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			match, matched, err := Evaluate(test.rules, test.flow)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("got match %+v, want an error", match)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if matched != test.wantMatched {
+				t.Fatalf("got matched %t, want %t", matched, test.wantMatched)
+			}
+			if !matched {
+				return
+			}
+			if match.Index != test.wantIndex || match.Reflected != test.wantReflected {
+				t.Errorf("got rule %d (reflected %t), want rule %d (reflected %t)", match.Index, match.Reflected, test.wantIndex, test.wantReflected)
+			}
+			if match.Rule != test.rules[test.wantIndex] {
+				t.Errorf("got rule %+v, want %+v", match.Rule, test.rules[test.wantIndex])
+			}
+		})
+	}
+}
+
+func TestEvaluateRuleError(t *testing.T) {
+	rules := []Rule{
+		testRule("web", ActionForward, "10.0.0.0/24", "443"),
+		testRule("invalid", ActionDrop, "10.0.0.1", "80-"),
+	}
+	flow := Flow{Direction: DirectionUplink, SourceIP: netip.MustParseAddr("10.0.0.1"), DestinationIP: netip.MustParseAddr("10.0.1.1")}
+
+	_, _, err := Evaluate(rules, flow)
+
+	var ruleErr *RuleError
+	if !errors.As(err, &ruleErr) || ruleErr.Index != 1 {
+		t.Fatalf("got error %v, want an error for rule 1", err)
+	}
+}
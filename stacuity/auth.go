@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenRefreshMargin - How long before expiry a cached access token is renewed
+const tokenRefreshMargin = time.Minute
+
+// ClientCredentials - OAuth2 client-credentials settings used to obtain short-lived access tokens
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+}
+
+// NewClientWithCredentials - Returns a Client that authenticates with OAuth2 client credentials,
+// fetching and renewing access tokens as they approach expiry.
+func NewClientWithCredentials(host *string, credentials ClientCredentials) (*Client, error) {
+	if credentials.ClientID == "" || credentials.ClientSecret == "" || credentials.TokenURL == "" {
+		return nil, errors.New("client id, client secret and token url are all required for client credentials authentication")
+	}
+
+	token := ""
+	c, err := NewClient(host, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	c.tokens = &tokenSource{
+		client: c,
+		config: clientcredentials.Config{
+			ClientID:     credentials.ClientID,
+			ClientSecret: credentials.ClientSecret,
+			TokenURL:     credentials.TokenURL,
+			Scopes:       credentials.Scopes,
+		},
+	}
+
+	return c, nil
+}
+
+// tokenSource caches an access token fetched with OAuth2 client credentials, renewing it
+// shortly before it expires and after the API rejects it. Tokens are fetched with the context
+// of the request that needs one, through the Client's HTTPClient, throttle and retry policy,
+// so transport settings apply to the token endpoint too.
+type tokenSource struct {
+	client *Client
+	config clientcredentials.Config
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// Token returns the cached token, or fetches a new one when there is none or it is about to
+// expire. Concurrent callers wait for a single fetch.
+func (s *tokenSource) Token(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && (s.token.Expiry.IsZero() || time.Until(s.token.Expiry) > tokenRefreshMargin) {
+		return s.token, nil
+	}
+
+	httpClient := &http.Client{Transport: tokenTransport{client: s.client}}
+	token, err := s.config.Token(context.WithValue(ctx, oauth2.HTTPClient, httpClient))
+	if err != nil {
+		return nil, err
+	}
+
+	s.token = token
+	return token, nil
+}
+
+// invalidate drops token from the cache if it is still the cached one, so that the next call
+// to Token fetches a new one.
+func (s *tokenSource) invalidate(token *oauth2.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+// tokenTransport - http.RoundTripper sending token requests with the Client's throttle and
+// retry policy
+type tokenTransport struct {
+	client *Client
+}
+
+func (t tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Fetching a token changes nothing, so the POST to the token endpoint may be retried.
+	policy := t.client.Retry
+	policy.RetryNonIdempotent = true
+
+	res, body, err := t.client.sendWithRetry(req, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	return res, nil
+}
+
+// authorize sets the Authorization header, from the client credentials token source when
+// configured and the static Token otherwise. It returns the access token used, if any.
+func (c *Client) authorize(req *http.Request) (*oauth2.Token, error) {
+	if c.tokens == nil {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		return nil, nil
+	}
+
+	token, err := c.tokens.Token(req.Context())
+	if err != nil {
+		return nil, errors.Join(ErrUnauthorized, err)
+	}
+
+	token.SetAuthHeader(req)
+	return token, nil
+}
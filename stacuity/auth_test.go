@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// authServer serves a token endpoint at /token, issuing tokens token-1, token-2 and so on after
+// tokenFailures failed attempts, and an API at /api that accepts only the tokens in accepted.
+type authServer struct {
+	tokenFailures int32
+	accepted      map[string]bool
+
+	tokenRequests atomic.Int32
+	issued        atomic.Int32
+	apiRequests   atomic.Int32
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/token":
+		if s.tokenRequests.Add(1) <= s.tokenFailures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, s.issued.Add(1))
+	case "/api":
+		s.apiRequests.Add(1)
+		if !s.accepted[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"data":"done"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newAuthClient returns a client authenticating with client credentials against server.
+func newAuthClient(t *testing.T, server *authServer) (*Client, string) {
+	t.Helper()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	client, err := NewClientWithCredentials(&httpServer.URL, ClientCredentials{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     httpServer.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Retry = testRetryPolicy()
+
+	return client, httpServer.URL + "/api"
+}
+
+func TestClientCredentialsAuthorization(t *testing.T) {
+	tests := map[string]struct {
+		server            *authServer
+		wantErr           bool
+		wantTokenRequests int32
+		wantAPIRequests   int32
+	}{
+		"token fetched once": {
+			server:            &authServer{accepted: map[string]bool{"token-1": true}},
+			wantTokenRequests: 1,
+			wantAPIRequests:   2,
+		},
+		"token endpoint failure retried": {
+			server:            &authServer{tokenFailures: 2, accepted: map[string]bool{"token-1": true}},
+			wantTokenRequests: 3,
+			wantAPIRequests:   2,
+		},
+		"revoked token renewed once": {
+			server:            &authServer{accepted: map[string]bool{"token-2": true}},
+			wantTokenRequests: 2,
+			wantAPIRequests:   3,
+		},
+		"token rejected after renewal": {
+			server:            &authServer{accepted: map[string]bool{}},
+			wantErr:           true,
+			wantTokenRequests: 2,
+			wantAPIRequests:   2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, url := newAuthClient(t, test.server)
+
+			var err error
+			for range 2 {
+				req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"name":"test"}`))
+				if _, _, err = client.doRequest(req); err != nil {
+					break
+				}
+			}
+
+			if test.wantErr != (err != nil) {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+			if test.wantErr && !IsUnauthorized(err) {
+				t.Errorf("got error %v, want an unauthorized error", err)
+			}
+			if got := test.server.tokenRequests.Load(); got != test.wantTokenRequests {
+				t.Errorf("got %d token requests, want %d", got, test.wantTokenRequests)
+			}
+			if got := test.server.apiRequests.Load(); got != test.wantAPIRequests {
+				t.Errorf("got %d API requests, want %d", got, test.wantAPIRequests)
+			}
+		})
+	}
+}
+
+func TestClientCredentialsTokenFetchCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	client, err := NewClientWithCredentials(&server.URL, ClientCredentials{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL + "/token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api", nil)
+	start := time.Now()
+	_, _, err = client.doRequest(req)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("token fetch took %s after the request was cancelled", elapsed)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CassetteMode - Whether a CassetteTransport records live traffic or replays a recording
+type CassetteMode string
+
+const (
+	// CassetteRecord sends requests to the real API and appends each exchange to the cassette.
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay answers requests from the cassette without touching the network.
+	CassetteReplay CassetteMode = "replay"
+)
+
+// ErrCassetteMiss - Returned in replay mode when no recorded interaction matches a request
+var ErrCassetteMiss = errors.New("no matching interaction in cassette")
+
+// Cassette - Recorded API traffic, stored as JSON. Secrets are scrubbed before anything is
+// written, so replayed responses carry placeholder values in secret fields.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction - One recorded request and the response it received
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest - The parts of a request used to match it on replay. URL holds the path and
+// query relative to the host, so a cassette can be replayed against any host.
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// CassetteResponse - A recorded response with secret headers and body fields scrubbed
+type CassetteResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// CassetteTransport - http.RoundTripper that records to, or replays from, a cassette file.
+// Interactions are replayed in recorded order for each method, URL and body, so repeated reads
+// of the same object see the same sequence of states as when they were recorded.
+type CassetteTransport struct {
+	file *CassetteFile
+	next http.RoundTripper
+}
+
+// CassetteFile - A cassette open for recording or replay. Every transport made from it shares
+// the one recording, so a test that configures many clients, as the provider does for each
+// Terraform command, records or replays one continuous sequence of interactions.
+type CassetteFile struct {
+	path string
+	mode CassetteMode
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// OpenCassette - Opens the cassette at path. In record mode any existing cassette is replaced
+// by an empty one; in replay mode the cassette must already exist.
+func OpenCassette(path string, mode CassetteMode) (*CassetteFile, error) {
+	file := &CassetteFile{path: path, mode: mode}
+
+	switch mode {
+	case CassetteRecord:
+		if err := file.save(); err != nil {
+			return nil, err
+		}
+	case CassetteReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &file.cassette); err != nil {
+			return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		file.used = make([]bool, len(file.cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q, expected %q or %q", mode, CassetteRecord, CassetteReplay)
+	}
+
+	return file, nil
+}
+
+// Transport - Returns a CassetteTransport for the cassette. In record mode live requests are
+// sent through next, or http.DefaultTransport when next is nil.
+func (f *CassetteFile) Transport(next http.RoundTripper) *CassetteTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &CassetteTransport{file: f, next: next}
+}
+
+// NewCassetteTransport - Opens the cassette at path and returns a transport for it, as
+// OpenCassette and Transport do.
+func NewCassetteTransport(path string, mode CassetteMode, next http.RoundTripper) (*CassetteTransport, error) {
+	file, err := OpenCassette(path, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Transport(next), nil
+}
+
+// UseCassette - Routes the Client's HTTP traffic through a transport for file, keeping the
+// current transport for live requests in record mode
+func (c *Client) UseCassette(file *CassetteFile) {
+	httpClient := *c.HTTPClient
+	httpClient.Transport = file.Transport(httpClient.Transport)
+	c.HTTPClient = &httpClient
+}
+
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.file.mode == CassetteReplay {
+		return t.replay(req)
+	}
+
+	return t.record(req)
+}
+
+func (t *CassetteTransport) record(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Scrubbing can change the body length, so the recorded length would no longer be accurate.
+	headers := redactHeaders(res.Header)
+	delete(headers, "Content-Length")
+
+	file := t.file
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	file.cassette.Interactions = append(file.cassette.Interactions, Interaction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Body:   redactBody(requestBody),
+		},
+		Response: CassetteResponse{
+			StatusCode: res.StatusCode,
+			Headers:    headers,
+			Body:       redactBody(body),
+		},
+	})
+
+	// The provider process can be stopped at any point, so the cassette is rewritten after
+	// every interaction rather than once at the end.
+	if err := file.save(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (t *CassetteTransport) replay(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	// Bodies are compared as recorded, after scrubbing, which also puts JSON in a canonical form.
+	body := redactBody(requestBody)
+
+	file := t.file
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	for i, interaction := range file.cassette.Interactions {
+		if file.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != req.URL.RequestURI() || interaction.Request.Body != body {
+			continue
+		}
+		file.used[i] = true
+
+		header := http.Header{}
+		for name, value := range interaction.Response.Headers {
+			header.Set(name, value)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrCassetteMiss, req.Method, req.URL.RequestURI())
+}
+
+// save writes the cassette atomically so a partially written file is never left behind.
+func (f *CassetteFile) save() error {
+	data, err := json.MarshalIndent(f.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+
+	temp := f.path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, f.path)
+}
+
+// readRequestBody returns a copy of the request body without consuming it.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	stacuity "stacuity.com/go_client"
+	"stacuity.com/go_client/fake"
+	"stacuity.com/go_client/models"
+)
+
+// cassetteSteps runs the same calls against a client for each step of a multi-step test, as
+// the provider does for each Terraform command, and returns what the API answered.
+var cassetteSteps = []func(ctx context.Context, client *stacuity.Client) (string, error){
+	func(ctx context.Context, client *stacuity.Client) (string, error) {
+		_, err := client.CreateEventMap(ctx, models.EventMapModifyItem{Name: "First", Moniker: "first", EventScope: "vslice"})
+		if err != nil {
+			return "", err
+		}
+		eventMap, err := client.GetEventMap(ctx, "first")
+		return eventMap.Name + "@" + eventMap.Version, err
+	},
+	func(ctx context.Context, client *stacuity.Client) (string, error) {
+		// A create with the same URL as in the first step, told apart only by its body.
+		_, err := client.CreateEventMap(ctx, models.EventMapModifyItem{Name: "Second", Moniker: "second", EventScope: "vslice"})
+		if err != nil {
+			return "", err
+		}
+		_, err = client.UpdateEventMap(ctx, "first", models.EventMapModifyItem{Name: "First renamed", Moniker: "first", EventScope: "vslice"}, "")
+		if err != nil {
+			return "", err
+		}
+		eventMap, err := client.GetEventMap(ctx, "first")
+		return eventMap.Name + "@" + eventMap.Version, err
+	},
+	func(ctx context.Context, client *stacuity.Client) (string, error) {
+		eventMaps, err := client.GetAllEventMaps(ctx, models.PagingState{})
+		names := []string{}
+		for _, eventMap := range eventMaps {
+			names = append(names, eventMap.Name)
+		}
+		return strings.Join(names, ","), err
+	},
+}
+
+func TestCassetteRecordThenReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassettes", "event_maps.json")
+
+	server := fake.NewServer()
+	host := server.HostURL()
+
+	// Record, configuring a new client for every step.
+	file, err := stacuity.OpenCassette(path, stacuity.CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recorded []string
+	for i, step := range cassetteSteps {
+		client := server.APIClient()
+		client.UseCassette(file)
+
+		result, err := step(ctx, client)
+		if err != nil {
+			t.Fatalf("recording step %d: %s", i+1, err)
+		}
+		recorded = append(recorded, result)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), fake.Token) {
+		t.Error("cassette contains the API token")
+	}
+
+	// Replay without a server, again with a new client for every step.
+	file, err = stacuity.OpenCassette(path, stacuity.CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, step := range cassetteSteps {
+		token := "replay"
+		client, _ := stacuity.NewClient(&host, &token)
+		client.UseCassette(file)
+
+		result, err := step(ctx, client)
+		if err != nil {
+			t.Fatalf("replaying step %d: %s", i+1, err)
+		}
+		if result != recorded[i] {
+			t.Errorf("replaying step %d returned %q, recorded %q", i+1, result, recorded[i])
+		}
+	}
+
+	// Every interaction has been used up, so a repeat of the last step has nothing to replay.
+	token := "replay"
+	client, _ := stacuity.NewClient(&host, &token)
+	client.UseCassette(file)
+	if _, err := cassetteSteps[2](ctx, client); !errors.Is(err, stacuity.ErrCassetteMiss) {
+		t.Errorf("repeated step returned %v, want %v", err, stacuity.ErrCassetteMiss)
+	}
+}
+
+func TestCassetteReplayMatchesBody(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := fake.NewServer()
+	host := server.HostURL()
+
+	file, err := stacuity.OpenCassette(path, stacuity.CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := server.APIClient()
+	client.UseCassette(file)
+	if _, err := client.CreateEventMap(ctx, models.EventMapModifyItem{Name: "First", Moniker: "first", EventScope: "vslice"}); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	if file, err = stacuity.OpenCassette(path, stacuity.CassetteReplay); err != nil {
+		t.Fatal(err)
+	}
+	token := "replay"
+	client, _ = stacuity.NewClient(&host, &token)
+	client.UseCassette(file)
+
+	_, err = client.CreateEventMap(ctx, models.EventMapModifyItem{Name: "Other", Moniker: "other", EventScope: "vslice"})
+	if !errors.Is(err, stacuity.ErrCassetteMiss) {
+		t.Errorf("create with a different body returned %v, want %v", err, stacuity.ErrCassetteMiss)
+	}
+
+	if _, err := client.CreateEventMap(ctx, models.EventMapModifyItem{Name: "First", Moniker: "first", EventScope: "vslice"}); err != nil {
+		t.Errorf("create with the recorded body: %s", err)
+	}
+}
+
+func TestCassetteRecordStartsAfresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, []byte(`{"interactions":[{"request":{"method":"GET","url":"/stale"},"response":{"status_code":200,"body":"{}"}}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stacuity.OpenCassette(path, stacuity.CassetteRecord); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "/stale") {
+		t.Errorf("recording kept interactions from an earlier run: %s", data)
+	}
+}
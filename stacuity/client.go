@@ -3,8 +3,8 @@
 package stacuity
 
 import (
-	"fmt"
-	"io/ioutil"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 )
@@ -16,6 +16,11 @@ type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
 	Token      string
+	// tokens, when set, supplies access tokens in place of Token.
+	tokens *tokenSource
+	Retry  RetryPolicy
+	// Throttle limits request rate and concurrency across all callers. Nil means unlimited.
+	Throttle *Throttle
 }
 
 func New(text string) error {
@@ -33,9 +38,10 @@ func (e *errorString) Error() string {
 
 func NewClient(host, authToken *string) (*Client, error) {
 	c := Client{
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
 		HostURL:    HostURL,
 		Token:      *authToken,
+		Retry:      DefaultRetryPolicy(),
 	}
 
 	if host != nil {
@@ -45,24 +51,98 @@ func NewClient(host, authToken *string) (*Client, error) {
 	return &c, nil
 }
 
-func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+// doRequest sends req, retrying as allowed by c.Retry, and returns the body and headers of
+// the successful response. An access token the API rejects, because it was revoked or rotated
+// before its expiry, is dropped and the request is sent once more with a new one.
+func (c *Client) doRequest(req *http.Request) ([]byte, http.Header, error) {
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	for reauthorized := false; ; reauthorized = true {
+		token, err := c.authorize(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res, body, err := c.sendWithRetry(req, c.Retry)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if res.StatusCode == http.StatusOK {
+			if err := checkEnvelope(res, body); err != nil {
+				return nil, nil, err
+			}
+			return body, res.Header, nil
+		}
+
+		if res.StatusCode != http.StatusUnauthorized || token == nil || reauthorized || !rewind(req) {
+			return nil, nil, newStatusError(res, body)
+		}
+		c.tokens.invalidate(token)
+	}
+}
+
+// sendWithRetry sends req, retrying as allowed by policy, and returns the last response with its body.
+func (c *Client) sendWithRetry(req *http.Request, policy RetryPolicy) (*http.Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && !rewind(req) {
+			return nil, nil, errors.New("request body cannot be sent again")
+		}
+
+		res, body, err := c.roundTrip(req, attempt)
+		if err == nil && res.StatusCode == http.StatusOK {
+			return res, body, nil
+		}
+
+		if !policy.retryable(req, res, err, attempt) {
+			return res, body, err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt, res))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rewind replaces the body of req, already read by an earlier attempt, with a fresh copy. It
+// reports whether req can be sent again.
+func rewind(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+
+	body, err := req.GetBody()
 	if err != nil {
-		return nil, err
+		return false
 	}
-	defer res.Body.Close()
+	req.Body = body
+	return true
+}
 
-	body, err := ioutil.ReadAll(res.Body)
+// roundTrip sends a single attempt of req, holding a Throttle slot until the body is read.
+func (c *Client) roundTrip(req *http.Request, attempt int) (*http.Response, []byte, error) {
+	release, err := c.Throttle.acquire(req.Context())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	defer release()
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	start := time.Now()
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		logRoundTrip(req, attempt, time.Since(start), nil, nil, err)
+		return nil, nil, err
 	}
+	defer res.Body.Close()
 
-	return body, err
+	body, err := io.ReadAll(res.Body)
+	logRoundTrip(req, attempt, time.Since(start), res, body, err)
+	return res, body, err
 }
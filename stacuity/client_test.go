@@ -0,0 +1,586 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	stacuity "stacuity.com/go_client"
+	"stacuity.com/go_client/fake"
+	"stacuity.com/go_client/models"
+)
+
+func newFakeClient(t *testing.T) *stacuity.Client {
+	t.Helper()
+
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+
+	return server.APIClient()
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestVSliceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+
+	vSlice := models.VSliceModifyItem{
+		Name:            "Test vSlice",
+		Moniker:         "test-vslice",
+		DNSServers:      []string{"1.1.1.1"},
+		DNSMode:         "default",
+		IpAddressFamily: "ipv4",
+		SubnetAddress:   "10.0.0.0/24",
+	}
+
+	created, err := client.CreateVSlice(ctx, vSlice)
+	if err != nil {
+		t.Fatalf("create: %s", err)
+	}
+	read, err := client.GetVSlice(ctx, vSlice.Moniker)
+	if err != nil {
+		t.Fatalf("read: %s", err)
+	}
+	if read.Id == "" || read.Name != vSlice.Name || read.Version == "" {
+		t.Errorf("read returned %+v", read)
+	}
+	if created.Data != read.Id || created.Adopted {
+		t.Errorf("create returned %+v, want the id %q of a new vSlice", created, read.Id)
+	}
+
+	// The object can be addressed by id as well as by moniker.
+	if byID, err := client.GetVSlice(ctx, read.Id); err != nil || byID.Moniker != vSlice.Moniker {
+		t.Errorf("read by id returned %+v, %v", byID, err)
+	}
+
+	vSlice.Name = "Renamed vSlice"
+	if _, err := client.UpdateVSlice(ctx, read.Id, vSlice, read.Version); err != nil {
+		t.Fatalf("update: %s", err)
+	}
+
+	updated, err := client.GetVSlice(ctx, read.Id)
+	if err != nil {
+		t.Fatalf("read after update: %s", err)
+	}
+	if updated.Name != vSlice.Name {
+		t.Errorf("read after update returned name %q, want %q", updated.Name, vSlice.Name)
+	}
+	if updated.Version == read.Version {
+		t.Errorf("version %s did not change on update", updated.Version)
+	}
+
+	list, err := client.GetAllVSlices(ctx, models.PagingState{})
+	if err != nil {
+		t.Fatalf("list: %s", err)
+	}
+	if len(list) != 1 || list[0].Id != read.Id {
+		t.Errorf("list returned %+v", list)
+	}
+
+	if _, err := client.DeleteVSlice(ctx, read.Id); err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+	if _, err := client.GetVSlice(ctx, read.Id); !stacuity.IsNotFound(err) {
+		t.Errorf("read after delete returned %v, want not found", err)
+	}
+	if _, err := client.DeleteVSlice(ctx, read.Id); !stacuity.IsNotFound(err) {
+		t.Errorf("second delete returned %v, want not found", err)
+	}
+}
+
+func TestUpdateStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+
+	handler := models.EventHandlerModifyItem{
+		Name:              "Webhook",
+		Moniker:           "webhook",
+		EventEndpointType: "webhook",
+		ConfigurationData: models.Configuration{WebhookConfig: &models.WebhookConfig{Url: "https://example.com/hook"}},
+	}
+	if _, err := client.CreateEventHandler(ctx, handler); err != nil {
+		t.Fatalf("create: %s", err)
+	}
+
+	stale, err := client.GetEventHandler(ctx, handler.Moniker)
+	if err != nil {
+		t.Fatalf("read: %s", err)
+	}
+
+	// Someone else changes the object after it was read.
+	handler.Name = "Changed elsewhere"
+	if _, err := client.UpdateEventHandler(ctx, handler.Moniker, handler, stale.Version); err != nil {
+		t.Fatalf("first update: %s", err)
+	}
+
+	handler.Name = "Changed here"
+	_, err = client.UpdateEventHandler(ctx, handler.Moniker, handler, stale.Version)
+	if !stacuity.IsPreconditionFailed(err) {
+		t.Fatalf("update with stale version returned %v, want precondition failed", err)
+	}
+
+	current, err := client.GetEventHandler(ctx, handler.Moniker)
+	if err != nil {
+		t.Fatalf("read after rejected update: %s", err)
+	}
+	if current.Name != "Changed elsewhere" {
+		t.Errorf("rejected update was applied, name is %q", current.Name)
+	}
+
+	// Without a precondition the update goes through.
+	if _, err := client.UpdateEventHandler(ctx, handler.Moniker, handler, ""); err != nil {
+		t.Errorf("update without version: %s", err)
+	}
+}
+
+func TestCreateIdempotencyKeyReplay(t *testing.T) {
+	client := newFakeClient(t)
+	eventMaps := stacuity.NewCollection[models.EventMapReadItem, models.EventMapModifyItem](client, "EventMaps")
+
+	eventMap := models.EventMapModifyItem{Name: "Map", Moniker: "map", EventScope: "vslice"}
+	ctx := stacuity.WithIdempotencyKey(context.Background(), stacuity.NewIdempotencyKey())
+
+	first, err := eventMaps.Create(ctx, eventMap)
+	if err != nil {
+		t.Fatalf("create: %s", err)
+	}
+
+	// Repeating the create with the same key answers with the original result.
+	replayed, err := eventMaps.Create(ctx, eventMap)
+	if err != nil {
+		t.Fatalf("repeated create: %s", err)
+	}
+	if replayed.Data != first.Data {
+		t.Errorf("repeated create returned %q, want %q", replayed.Data, first.Data)
+	}
+
+	list, err := eventMaps.ListAll(context.Background(), models.PagingState{})
+	if err != nil {
+		t.Fatalf("list: %s", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("repeated create stored %d objects, want 1", len(list))
+	}
+
+	// A different key is a different create, which clashes with the existing moniker.
+	other := stacuity.WithIdempotencyKey(context.Background(), stacuity.NewIdempotencyKey())
+	if _, err := eventMaps.Create(other, eventMap); !stacuity.IsConflict(err) {
+		t.Errorf("create with a new key returned %v, want conflict", err)
+	}
+}
+
+func TestEventMapSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+
+	handler := models.EventHandlerModifyItem{Name: "Webhook", Moniker: "webhook", EventEndpointType: "webhook"}
+	if _, err := client.CreateEventHandler(ctx, handler); err != nil {
+		t.Fatalf("create handler: %s", err)
+	}
+	if _, err := client.CreateEventMap(ctx, models.EventMapModifyItem{Name: "Map", Moniker: "map", EventScope: "vslice"}); err != nil {
+		t.Fatalf("create map: %s", err)
+	}
+
+	added, err := client.AddEventMapSubscriptions(ctx, []models.EventMapSubscriptionModifyItem{
+		{EventEndpointId: handler.Moniker, EventTypeId: "endpoint-online"},
+		{EventEndpointId: handler.Moniker, EventTypeId: "endpoint-offline"},
+	}, "map")
+	if err != nil {
+		t.Fatalf("add subscriptions: %s", err)
+	}
+	if len(added.Data) != 2 {
+		t.Fatalf("add subscriptions returned %d items, want 2", len(added.Data))
+	}
+
+	subscriptions, err := client.GetEventMapSubscriptions(ctx, "map")
+	if err != nil {
+		t.Fatalf("list subscriptions: %s", err)
+	}
+	if len(subscriptions) != 2 || subscriptions[0].EventEndpoint.Moniker != handler.Moniker {
+		t.Fatalf("list subscriptions returned %+v", subscriptions)
+	}
+
+	if _, err := client.DeleteEventMapSubscription(ctx, "map", subscriptions[0].Id); err != nil {
+		t.Fatalf("delete subscription: %s", err)
+	}
+
+	subscriptions, err = client.GetEventMapSubscriptions(ctx, "map")
+	if err != nil {
+		t.Fatalf("list subscriptions after delete: %s", err)
+	}
+	if len(subscriptions) != 1 || subscriptions[0].EventType.Moniker != "endpoint-offline" {
+		t.Errorf("list subscriptions after delete returned %+v", subscriptions)
+	}
+
+	// A subscription to an unknown handler is rejected without storing the valid ones.
+	_, err = client.AddEventMapSubscriptions(ctx, []models.EventMapSubscriptionModifyItem{
+		{EventEndpointId: handler.Moniker, EventTypeId: "endpoint-online"},
+		{EventEndpointId: "missing", EventTypeId: "endpoint-online"},
+	}, "map")
+	if !stacuity.IsValidation(err) {
+		t.Errorf("add subscription to unknown handler returned %v, want validation error", err)
+	}
+	if subscriptions, _ = client.GetEventMapSubscriptions(ctx, "map"); len(subscriptions) != 1 {
+		t.Errorf("rejected request stored subscriptions: %+v", subscriptions)
+	}
+
+	// Deleting the map deletes its subscriptions.
+	if _, err := client.DeleteEventMap(ctx, "map"); err != nil {
+		t.Fatalf("delete map: %s", err)
+	}
+	if _, err := client.GetEventMapSubscriptions(ctx, "map"); !stacuity.IsNotFound(err) {
+		t.Errorf("list subscriptions of deleted map returned %v, want not found", err)
+	}
+}
+
+func TestOperatorPolicyEntries(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+
+	if _, err := client.CreateOperatorPolicy(ctx, models.OperatorPolicyModifyItem{Name: "Policy", Moniker: "policy"}); err != nil {
+		t.Fatalf("create policy: %s", err)
+	}
+
+	_, err := client.AddOperatorPolicyEntries(ctx, []models.OperatorPolicyEntryModifyItem{
+		{OperatorId: ptr[int32](1), SteeringProfileEntryAction: "allow"},
+		{Iso3: ptr("GBR"), SteeringProfileEntryAction: "deny"},
+	}, "policy")
+	if err != nil {
+		t.Fatalf("add entries: %s", err)
+	}
+
+	entries, err := client.GetOperatorPolicyEntries(ctx, "policy")
+	if err != nil {
+		t.Fatalf("list entries: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("list entries returned %+v", entries)
+	}
+
+	update := models.OperatorPolicyEntryModifyItem{OperatorId: ptr[int32](2), SteeringProfileEntryAction: "deny"}
+	if _, err := client.UpdateOperatorPolicyEntry(ctx, "policy", entries[0].Id, update); err != nil {
+		t.Fatalf("update entry: %s", err)
+	}
+	if _, err := client.DeleteOperatorPolicyEntry(ctx, "policy", entries[1].Id); err != nil {
+		t.Fatalf("delete entry: %s", err)
+	}
+
+	entries, err = client.GetOperatorPolicyEntries(ctx, "policy")
+	if err != nil {
+		t.Fatalf("list entries after changes: %s", err)
+	}
+	if len(entries) != 1 || entries[0].OperatorId == nil || *entries[0].OperatorId != 2 || entries[0].SteeringProfileEntryAction.Moniker != "deny" {
+		t.Errorf("list entries after changes returned %+v", entries)
+	}
+
+	if _, err := client.DeleteOperatorPolicyEntry(ctx, "policy", entries[0].Id+"-missing"); !stacuity.IsNotFound(err) {
+		t.Errorf("delete of unknown entry returned %v, want not found", err)
+	}
+}
+
+func TestRegionalPolicyEntries(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient(t)
+
+	if _, err := client.CreateRegionalPolicy(ctx, models.RegionalPolicyModifyItem{Name: "Policy", Moniker: "policy"}); err != nil {
+		t.Fatalf("create policy: %s", err)
+	}
+
+	_, err := client.AddRegionalPolicyEntries(ctx, []models.RegionalPolicyEntryModifyItem{
+		{RegionalGatewayId: ptr("eu-west"), Iso3: ptr("GBR")},
+		{RegionalGatewayId: ptr("us-east"), OperatorId: ptr[int32](7)},
+	}, "policy")
+	if err != nil {
+		t.Fatalf("add entries: %s", err)
+	}
+
+	entries, err := client.GetRegionalPolicyEntries(ctx, "policy")
+	if err != nil {
+		t.Fatalf("list entries: %s", err)
+	}
+	if len(entries) != 2 || entries[0].RegionalGateway.Moniker != "eu-west" {
+		t.Fatalf("list entries returned %+v", entries)
+	}
+
+	update := models.RegionalPolicyEntryModifyItem{RegionalGatewayId: ptr("eu-central"), Iso3: ptr("DEU")}
+	if _, err := client.UpdateRegionalPolicyEntry(ctx, "policy", entries[0].Id, update); err != nil {
+		t.Fatalf("update entry: %s", err)
+	}
+	if _, err := client.DeleteRegionalPolicyEntry(ctx, "policy", entries[1].Id); err != nil {
+		t.Fatalf("delete entry: %s", err)
+	}
+
+	entries, err = client.GetRegionalPolicyEntries(ctx, "policy")
+	if err != nil {
+		t.Fatalf("list entries after changes: %s", err)
+	}
+	if len(entries) != 1 || entries[0].RegionalGateway.Moniker != "eu-central" || entries[0].Iso3 == nil || *entries[0].Iso3 != "DEU" {
+		t.Errorf("list entries after changes returned %+v", entries)
+	}
+
+	// An entry without a regional gateway is rejected.
+	if _, err := client.AddRegionalPolicyEntries(ctx, []models.RegionalPolicyEntryModifyItem{{Iso3: ptr("FRA")}}, "policy"); !stacuity.IsValidation(err) {
+		t.Errorf("add entry without gateway returned %v, want validation error", err)
+	}
+}
+
+// pageCounter counts the list requests sent through it and fails those at or past failAt.
+type pageCounter struct {
+	next   http.RoundTripper
+	pages  int
+	failAt int
+}
+
+func (p *pageCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && req.URL.Query().Has("limit") {
+		p.pages++
+		if offset, _ := strconv.Atoi(req.URL.Query().Get("offset")); p.failAt > 0 && offset >= p.failAt {
+			return nil, fmt.Errorf("connection reset")
+		}
+	}
+
+	return p.next.RoundTrip(req)
+}
+
+func newPagedClient(t *testing.T, count int, failAt int) (*stacuity.Client, *pageCounter) {
+	t.Helper()
+
+	client := newFakeClient(t)
+	for i := range count {
+		vSlice := models.VSliceModifyItem{Name: fmt.Sprintf("vSlice %d", i), Moniker: fmt.Sprintf("vslice-%d", i), DNSMode: "auto"}
+		if _, err := client.CreateVSlice(context.Background(), vSlice); err != nil {
+			t.Fatalf("create: %s", err)
+		}
+	}
+
+	counter := &pageCounter{next: client.HTTPClient.Transport, failAt: failAt}
+	client.HTTPClient.Transport = counter
+	client.Retry.MaxAttempts = 1
+
+	return client, counter
+}
+
+func TestAllVSlicesPaging(t *testing.T) {
+	client, counter := newPagedClient(t, 7, 0)
+
+	vSlices, err := client.GetAllVSlices(context.Background(), models.PagingState{Limit: 3})
+	if err != nil {
+		t.Fatalf("list: %s", err)
+	}
+	if len(vSlices) != 7 || vSlices[0].Moniker != "vslice-0" || vSlices[6].Moniker != "vslice-6" {
+		t.Errorf("list returned %d vSlices: %+v", len(vSlices), vSlices)
+	}
+	if counter.pages != 3 {
+		t.Errorf("fetched %d pages, want 3", counter.pages)
+	}
+
+	// A single page is not followed by the rest.
+	counter.pages = 0
+	page, err := client.GetVSlices(context.Background(), models.PagingState{Limit: 3, Offset: 3})
+	if err != nil {
+		t.Fatalf("list page: %s", err)
+	}
+	if len(page) != 3 || page[0].Moniker != "vslice-3" || counter.pages != 1 {
+		t.Errorf("list page returned %+v from %d pages", page, counter.pages)
+	}
+}
+
+func TestAllVSlicesStopsEarly(t *testing.T) {
+	client, counter := newPagedClient(t, 7, 0)
+
+	var monikers []string
+	for vSlice, err := range client.AllVSlices(context.Background(), models.PagingState{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("list: %s", err)
+		}
+		monikers = append(monikers, vSlice.Moniker)
+		if vSlice.Moniker == "vslice-2" {
+			break
+		}
+	}
+
+	if len(monikers) != 3 {
+		t.Errorf("got %v", monikers)
+	}
+	if counter.pages != 2 {
+		t.Errorf("fetched %d pages after stopping early, want 2", counter.pages)
+	}
+}
+
+func TestAllVSlicesErrorWhilePaging(t *testing.T) {
+	client, counter := newPagedClient(t, 7, 4)
+
+	var monikers []string
+	var gotErr error
+	for vSlice, err := range client.AllVSlices(context.Background(), models.PagingState{Limit: 2}) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		monikers = append(monikers, vSlice.Moniker)
+	}
+
+	if gotErr == nil {
+		t.Fatal("expected an error from the third page")
+	}
+	if len(monikers) != 4 || counter.pages != 3 {
+		t.Errorf("got %v from %d pages before the error, want 4 vSlices from 3 pages", monikers, counter.pages)
+	}
+
+	// ListAll returns the error along with the vSlices read before it.
+	vSlices, err := client.GetAllVSlices(context.Background(), models.PagingState{Limit: 2})
+	if err == nil || len(vSlices) != 4 {
+		t.Errorf("list returned %d vSlices and error %v, want 4 and an error", len(vSlices), err)
+	}
+}
+
+// lossyTransport forwards requests but loses the response to the first POST, as a timeout
+// after the server committed the create would. It drops Idempotency-Key headers, standing in
+// for an API that does not deduplicate creates, so the retried POST clashes with the first.
+type lossyTransport struct {
+	next http.RoundTripper
+	lost bool
+}
+
+func (l *lossyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Del(stacuity.IdempotencyKeyHeader)
+
+	res, err := l.next.RoundTrip(req)
+	if err == nil && req.Method == http.MethodPost && !l.lost {
+		l.lost = true
+		res.Body.Close()
+		return nil, fmt.Errorf("connection reset")
+	}
+
+	return res, err
+}
+
+func TestCreateAdoptsAfterLostResponse(t *testing.T) {
+	tests := map[string]struct {
+		create func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error)
+		list   func(ctx context.Context, client *stacuity.Client) ([]string, error)
+	}{
+		"endpoint group": {
+			create: func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error) {
+				return client.CreateEndpointGroup(ctx, models.EndpointGroupModifyItem{
+					Name: name, Moniker: "group", VSlice: "vslice", RegionalGatewayPolicy: "gateway", IPAllocationType: "static", EventMap: ptr("events"),
+				})
+			},
+			list: func(ctx context.Context, client *stacuity.Client) ([]string, error) {
+				items, err := client.GetAllEndpointGroups(ctx, models.PagingState{})
+				ids := []string{}
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				return ids, err
+			},
+		},
+		"event handler": {
+			create: func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error) {
+				return client.CreateEventHandler(ctx, models.EventHandlerModifyItem{
+					Name: name, Moniker: "handler", EventEndpointType: "webhook",
+					ConfigurationData: models.Configuration{WebhookConfig: &models.WebhookConfig{Url: "https://example.com/hook", Password: ptr("secret")}},
+				})
+			},
+			list: func(ctx context.Context, client *stacuity.Client) ([]string, error) {
+				items, err := client.GetAllEventHandlers(ctx, models.PagingState{})
+				ids := []string{}
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				return ids, err
+			},
+		},
+		"event map": {
+			create: func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error) {
+				return client.CreateEventMap(ctx, models.EventMapModifyItem{Name: name, Moniker: "map", EventScope: "vslice"})
+			},
+			list: func(ctx context.Context, client *stacuity.Client) ([]string, error) {
+				items, err := client.GetAllEventMaps(ctx, models.PagingState{})
+				ids := []string{}
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				return ids, err
+			},
+		},
+		"operator policy": {
+			create: func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error) {
+				return client.CreateOperatorPolicy(ctx, models.OperatorPolicyModifyItem{Name: name, Moniker: "operators"})
+			},
+			list: func(ctx context.Context, client *stacuity.Client) ([]string, error) {
+				items, err := client.GetAllOperatorPolicies(ctx, models.PagingState{})
+				ids := []string{}
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				return ids, err
+			},
+		},
+		"regional policy": {
+			create: func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error) {
+				return client.CreateRegionalPolicy(ctx, models.RegionalPolicyModifyItem{Name: name, Moniker: "regions"})
+			},
+			list: func(ctx context.Context, client *stacuity.Client) ([]string, error) {
+				items, err := client.GetAllRegionalPolicies(ctx, models.PagingState{})
+				ids := []string{}
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				return ids, err
+			},
+		},
+		"routing target": {
+			create: func(ctx context.Context, client *stacuity.Client, name string) (*models.Result, error) {
+				return client.CreateRoutingTarget(ctx, models.RoutingTargetModifyItem{
+					Name: name, Moniker: "target", RoutingTargetType: "wireguard", RoutingRedundancyZoneMoniker: "zone-a", VSlice: "vslice", RoutingTargetTypeInstanceId: "instance",
+				})
+			},
+			list: func(ctx context.Context, client *stacuity.Client) ([]string, error) {
+				items, err := client.GetAllRoutingTargets(ctx, models.PagingState{})
+				ids := []string{}
+				for _, item := range items {
+					ids = append(ids, item.Id)
+				}
+				return ids, err
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient(t)
+			client.HTTPClient.Transport = &lossyTransport{next: client.HTTPClient.Transport}
+			client.Retry.MinBackoff = time.Millisecond
+			ctx := stacuity.WithIdempotencyKey(context.Background(), stacuity.NewIdempotencyKey())
+
+			result, err := test.create(ctx, client, "Original")
+			if err != nil {
+				t.Fatalf("create after a lost response: %s", err)
+			}
+			ids, err := test.list(context.Background(), client)
+			if err != nil || len(ids) != 1 {
+				t.Fatalf("list returned ids %q, %v, want 1", ids, err)
+			}
+
+			// The adopted object is reported as a create would report it.
+			if !result.Success || !result.Adopted || result.Data != ids[0] {
+				t.Errorf("create after a lost response returned %+v, want the adopted id %q", result, ids[0])
+			}
+
+			// An existing object with a different configuration is not adopted.
+			if _, err := test.create(context.Background(), client, "Different"); !stacuity.IsConflict(err) {
+				t.Errorf("create clashing with a different object returned %v, want conflict", err)
+			}
+		})
+	}
+}
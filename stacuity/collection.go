@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+	"stacuity.com/go_client/models"
+)
+
+// Collection - CRUD access to one Stacuity object type. Read is the model returned by the API
+// and Modify is the payload accepted by create and update calls.
+type Collection[Read any, Modify any] struct {
+	client *Client
+	path   string
+}
+
+// NewCollection - Returns a Collection for the objects served under path, e.g. "vslices"
+func NewCollection[Read any, Modify any](c *Client, path string) Collection[Read, Modify] {
+	return Collection[Read, Modify]{client: c, path: path}
+}
+
+// ListPage - Returns a single page of objects along with the paging totals
+func (col Collection[Read, Modify]) ListPage(ctx context.Context, pagingState models.PagingState) (models.Page[Read], error) {
+	querystring, _ := query.Values(pagingState)
+	apiResponse := models.Page[Read]{}
+	err := col.client.send(ctx, http.MethodGet, col.path+"?"+querystring.Encode(), nil, &apiResponse)
+	return apiResponse, err
+}
+
+// List - Returns the objects of a single page
+func (col Collection[Read, Modify]) List(ctx context.Context, pagingState models.PagingState) ([]Read, error) {
+	items := []Read{}
+	apiResponse, err := col.ListPage(ctx, pagingState)
+	if err != nil {
+		return items, err
+	}
+
+	return append(items, apiResponse.Data...), nil
+}
+
+// All - Iterates over every object matching pagingState, fetching pages as needed
+func (col Collection[Read, Modify]) All(ctx context.Context, pagingState models.PagingState) iter.Seq2[Read, error] {
+	return allPages(ctx, pagingState, func(ctx context.Context, page models.PagingState) ([]Read, int32, error) {
+		apiResponse, err := col.ListPage(ctx, page)
+		return apiResponse.Data, apiResponse.TotalItems, err
+	})
+}
+
+// ListAll - Returns every object matching pagingState across all pages
+func (col Collection[Read, Modify]) ListAll(ctx context.Context, pagingState models.PagingState) ([]Read, error) {
+	return collectPages(col.All(ctx, pagingState))
+}
+
+// Get - Returns the object with the given moniker or id
+func (col Collection[Read, Modify]) Get(ctx context.Context, id string) (Read, error) {
+	item, _, err := col.GetWithVersion(ctx, id)
+	return item, err
+}
+
+// GetWithVersion - Returns the object with the given moniker or id, along with its version:
+// the ETag of the response, or empty if the API did not send one
+func (col Collection[Read, Modify]) GetWithVersion(ctx context.Context, id string) (Read, string, error) {
+	apiResponse := models.Single[Read]{}
+	header, err := col.client.sendWithHeader(ctx, http.MethodGet, col.itemPath(id), nil, nil, &apiResponse)
+	if err != nil {
+		return apiResponse.Data, "", err
+	}
+
+	return apiResponse.Data, header.Get("ETag"), nil
+}
+
+// Create - Creates a new object, sending the idempotency key from ctx when one is set
+func (col Collection[Read, Modify]) Create(ctx context.Context, item Modify) (*models.Result, error) {
+	var header http.Header
+	if key := idempotencyKey(ctx); key != "" {
+		header = http.Header{IdempotencyKeyHeader: []string{key}}
+	}
+
+	return col.resultWithHeader(ctx, http.MethodPost, col.path, header, item)
+}
+
+// CreateOrAdopt - Creates a new object. If the API reports that moniker is already taken and
+// the existing object matches item, that object is adopted: the result carries its id, as
+// returned by id, in Data like a create does, with Adopted set. This makes a create whose
+// response was lost, e.g. to a timeout after the server committed it, safe to repeat. A clash
+// with an object that does not match is returned as the original conflict.
+func (col Collection[Read, Modify]) CreateOrAdopt(ctx context.Context, item Modify, moniker string, matches func(existing Read, want Modify) bool, id func(existing Read) string) (*models.Result, error) {
+	apiResponse, err := col.Create(ctx, item)
+	if err == nil || !IsConflict(err) || moniker == "" {
+		return apiResponse, err
+	}
+
+	existing, getErr := col.Get(ctx, moniker)
+	if getErr != nil || !matches(existing, item) {
+		return nil, err
+	}
+
+	return &models.Result{
+		Success:  true,
+		Messages: []string{"Adopted existing object " + moniker},
+		Data:     id(existing),
+		Adopted:  true,
+	}, nil
+}
+
+// Update - Replaces the object with the given moniker or id. Unless version is empty, it is
+// sent as If-Match and the update fails with ErrPreconditionFailed if the object has changed
+// since it was read with that version.
+func (col Collection[Read, Modify]) Update(ctx context.Context, id string, item Modify, version string) (*models.Result, error) {
+	var header http.Header
+	if version != "" {
+		header = http.Header{"If-Match": []string{version}}
+	}
+
+	return col.resultWithHeader(ctx, http.MethodPut, col.itemPath(id), header, item)
+}
+
+// Delete - Deletes the object with the given moniker or id
+func (col Collection[Read, Modify]) Delete(ctx context.Context, id string) (*models.Result, error) {
+	return col.result(ctx, http.MethodDelete, col.itemPath(id), nil)
+}
+
+// ItemPath - Returns the path of a child of the object, e.g. its "entries"
+func (col Collection[Read, Modify]) ItemPath(id string, child string) string {
+	return col.itemPath(id) + "/" + child
+}
+
+func (col Collection[Read, Modify]) itemPath(id string) string {
+	return col.path + "/" + url.PathEscape(id)
+}
+
+func (col Collection[Read, Modify]) result(ctx context.Context, method, path string, payload any) (*models.Result, error) {
+	return col.resultWithHeader(ctx, method, path, nil, payload)
+}
+
+func (col Collection[Read, Modify]) resultWithHeader(ctx context.Context, method, path string, header http.Header, payload any) (*models.Result, error) {
+	apiResponse := models.Result{}
+	if _, err := col.client.sendWithHeader(ctx, method, path, header, payload, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	return &apiResponse, nil
+}
+
+// send issues a request against HostURL, encoding payload (if any) as JSON and decoding the
+// response envelope into out. Envelopes with success=false are turned into an *APIError by doRequest.
+func (c *Client) send(ctx context.Context, method, path string, payload any, out any) error {
+	_, err := c.sendWithHeader(ctx, method, path, nil, payload, out)
+	return err
+}
+
+// sendWithHeader is send with additional request headers, returning the response headers.
+func (c *Client) sendWithHeader(ctx context.Context, method, path string, header http.Header, payload any, out any) (http.Header, error) {
+	var body io.Reader
+	if payload != nil {
+		rb, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(rb)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.HostURL, path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	responseBody, responseHeader, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseHeader, json.Unmarshal(responseBody, out)
+}
@@ -3,152 +3,85 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
+	"iter"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetEndpointGroups - Returns list of EndpointGroups
-func (c *Client) GetEndpointGroups(pagingState models.PagingState) ([]models.EndpointGroupReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	EndpointGroupItems := []models.EndpointGroupReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EndpointGroups?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return EndpointGroupItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return EndpointGroupItems, err
-	}
+// endpointGroups - Returns the collection backing the EndpointGroup methods
+func (c *Client) endpointGroups() Collection[models.EndpointGroupReadItem, models.EndpointGroupModifyItem] {
+	return NewCollection[models.EndpointGroupReadItem, models.EndpointGroupModifyItem](c, "EndpointGroups")
+}
 
-	apiResponse := models.EndpointGroupList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return EndpointGroupItems, err
-	}
+// GetEndpointGroups - Returns list of EndpointGroups
+func (c *Client) GetEndpointGroups(ctx context.Context, pagingState models.PagingState) ([]models.EndpointGroupReadItem, error) {
+	return c.endpointGroups().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return EndpointGroupItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetEndpointGroupsPage - Returns a single page of EndpointGroups along with the paging totals
+func (c *Client) GetEndpointGroupsPage(ctx context.Context, pagingState models.PagingState) (models.EndpointGroupList, error) {
+	return c.endpointGroups().ListPage(ctx, pagingState)
+}
 
-	EndpointGroupItems = append(EndpointGroupItems, apiResponse.Data...)
+// AllEndpointGroups - Iterates over all EndpointGroups matching pagingState, fetching pages as needed
+func (c *Client) AllEndpointGroups(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.EndpointGroupReadItem, error] {
+	return c.endpointGroups().All(ctx, pagingState)
+}
 
-	return EndpointGroupItems, nil
+// GetAllEndpointGroups - Returns all EndpointGroups matching pagingState across all pages
+func (c *Client) GetAllEndpointGroups(ctx context.Context, pagingState models.PagingState) ([]models.EndpointGroupReadItem, error) {
+	return c.endpointGroups().ListAll(ctx, pagingState)
 }
 
 // GetEndpointGroup - Returns a specific EndpointGroup
-func (c *Client) GetEndpointGroup(EndpointGroupId string) (models.EndpointGroupReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EndpointGroups/%s", c.HostURL, EndpointGroupId), nil)
-	if err != nil {
-		return models.EndpointGroupReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.EndpointGroupReadItem{}, err
-	}
-
-	apiResponse := models.EndpointGroupSingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.EndpointGroupReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.EndpointGroupReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return apiResponse.Data, nil
+func (c *Client) GetEndpointGroup(ctx context.Context, EndpointGroupId string) (models.EndpointGroupReadItem, error) {
+	item, version, err := c.endpointGroups().GetWithVersion(ctx, EndpointGroupId)
+	item.Version = version
+	return item, err
 }
 
-// CreateEndpointGroup - Create a new Routing Policy
-func (c *Client) CreateEndpointGroup(EndpointGroup models.EndpointGroupModifyItem) (*models.EndpointGroupResponse, error) {
-	rb, err := json.Marshal(EndpointGroup)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/EndpointGroups", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EndpointGroupResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// CreateEndpointGroup - Create a new Endpoint Group, adopting an existing group with the same moniker and configuration
+func (c *Client) CreateEndpointGroup(ctx context.Context, EndpointGroup models.EndpointGroupModifyItem) (*models.EndpointGroupResponse, error) {
+	return c.endpointGroups().CreateOrAdopt(ctx, EndpointGroup, EndpointGroup.Moniker, endpointGroupMatches, func(existing models.EndpointGroupReadItem) string {
+		return existing.Id
+	})
 }
 
 // UpdateEndpointGroup - Update a new Routing Policy
-func (c *Client) UpdateEndpointGroup(EndpointGroupId string, EndpointGroup models.EndpointGroupModifyItem) (*models.EndpointGroupResponse, error) {
-	rb, err := json.Marshal(EndpointGroup)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/EndpointGroups/%s", c.HostURL, EndpointGroupId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EndpointGroupResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateEndpointGroup(ctx context.Context, EndpointGroupId string, EndpointGroup models.EndpointGroupModifyItem, version string) (*models.EndpointGroupResponse, error) {
+	return c.endpointGroups().Update(ctx, EndpointGroupId, EndpointGroup, version)
 }
 
 // DeleteEndpointGroup - Delete a EndpointGroup
-func (c *Client) DeleteEndpointGroup(EndpointGroupId string) (*models.EndpointGroupResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/EndpointGroups/%s", c.HostURL, EndpointGroupId), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) DeleteEndpointGroup(ctx context.Context, EndpointGroupId string) (*models.EndpointGroupResponse, error) {
+	return c.endpointGroups().Delete(ctx, EndpointGroupId)
+}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// endpointGroupMatches reports whether an existing endpoint group has the configuration requested by want.
+func endpointGroupMatches(existing models.EndpointGroupReadItem, want models.EndpointGroupModifyItem) bool {
+	eventMapMatches := want.EventMap == nil && existing.EventMap == nil
+	if want.EventMap != nil && existing.EventMap != nil {
+		eventMapMatches = refMatches(existing.EventMap.Id, existing.EventMap.Moniker, *want.EventMap)
 	}
 
-	apiResponse := models.EndpointGroupResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
+	routingPolicyMatches := want.RoutingPolicy == nil && existing.RoutingPolicy == nil
+	if want.RoutingPolicy != nil && existing.RoutingPolicy != nil {
+		routingPolicyMatches = refMatches("", existing.RoutingPolicy.Moniker, *want.RoutingPolicy)
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
+	steeringProfileMatches := want.SteeringProfile == nil && existing.SteeringProfile == nil
+	if want.SteeringProfile != nil && existing.SteeringProfile != nil {
+		steeringProfileMatches = refMatches(existing.SteeringProfile.Id, existing.SteeringProfile.Moniker, *want.SteeringProfile)
 	}
 
-	return &apiResponse, nil
+	return existing.Name == want.Name &&
+		existing.IPAllocationType.Moniker == want.IPAllocationType &&
+		refMatches(existing.VSlice.Id, existing.VSlice.Moniker, want.VSlice) &&
+		refMatches(existing.RegionalGatewayPolicy.Id, existing.RegionalGatewayPolicy.Moniker, want.RegionalGatewayPolicy) &&
+		eventMapMatches &&
+		routingPolicyMatches &&
+		steeringProfileMatches
 }
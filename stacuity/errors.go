@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for the classes of failure reported by the Stacuity API. An *APIError
+// matches exactly one of these with errors.Is.
+var (
+	ErrNotFound           = errors.New("stacuity: not found")
+	ErrConflict           = errors.New("stacuity: conflict")
+	ErrPreconditionFailed = errors.New("stacuity: object changed since it was read")
+	ErrUnauthorized       = errors.New("stacuity: unauthorized")
+	ErrValidation         = errors.New("stacuity: validation failed")
+	ErrRateLimited        = errors.New("stacuity: rate limited")
+	ErrServer             = errors.New("stacuity: server error")
+)
+
+// requestIDHeaders - Response headers checked, in order, for the API request identifier
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"}
+
+// APIError - A failed call to the Stacuity API, either a non-200 status or an envelope with success=false
+type APIError struct {
+	// StatusCode is the HTTP status of the response. Envelope failures are usually reported with 200.
+	StatusCode int
+	// Messages holds the messages from the API envelope, if one could be decoded.
+	Messages []string
+	// RequestID is the identifier the API assigned to the request, when provided.
+	RequestID string
+	// Body is the response body with secret fields masked, kept for responses that did not carry an envelope.
+	Body string
+
+	kind error
+}
+
+func (e *APIError) Error() string {
+	var detail string
+	if len(e.Messages) > 0 {
+		detail = strings.Join(e.Messages, " ")
+	} else {
+		detail = fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+	}
+
+	if e.RequestID != "" {
+		return detail + " (request id: " + e.RequestID + ")"
+	}
+
+	return detail
+}
+
+// Is reports whether target is the sentinel matching the class of this error.
+func (e *APIError) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+// Kind - Returns the sentinel error describing the class of this error
+func (e *APIError) Kind() error {
+	return e.kind
+}
+
+// IsNotFound - Reports whether err means the requested object does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict - Reports whether err means the request clashed with an existing object
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsPreconditionFailed - Reports whether err means an update was rejected because the object
+// no longer has the version it was read with
+func IsPreconditionFailed(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+// IsUnauthorized - Reports whether err means the credentials were rejected
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsValidation - Reports whether err means the API rejected the request payload
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsRateLimited - Reports whether err means the API throttled the request
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError - Reports whether err means the API failed internally
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServer)
+}
+
+// apiEnvelope - The fields shared by every Stacuity API response
+type apiEnvelope struct {
+	Success  *bool    `json:"success"`
+	Messages []string `json:"messages"`
+}
+
+// newStatusError builds an *APIError for a response with a non-200 status.
+func newStatusError(res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  requestID(res),
+		Body:       redactBody(body),
+		kind:       kindForStatus(res.StatusCode),
+	}
+
+	envelope := apiEnvelope{}
+	if json.Unmarshal(body, &envelope) == nil {
+		apiErr.Messages = envelope.Messages
+	}
+
+	// Some endpoints answer 400 for missing records, so trust the message over the status.
+	if kind := kindForMessages(apiErr.Messages); kind != nil && apiErr.kind == ErrValidation {
+		apiErr.kind = kind
+	}
+
+	return apiErr
+}
+
+// checkEnvelope returns an *APIError when a 200 response carries success=false.
+func checkEnvelope(res *http.Response, body []byte) error {
+	envelope := apiEnvelope{}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Success == nil || *envelope.Success {
+		return nil
+	}
+
+	return newEnvelopeError(res.StatusCode, requestID(res), envelope.Messages)
+}
+
+// newEnvelopeError builds an *APIError from the messages of an unsuccessful envelope.
+func newEnvelopeError(statusCode int, requestID string, messages []string) *APIError {
+	kind := kindForMessages(messages)
+	if kind == nil {
+		kind = ErrValidation
+	}
+
+	return &APIError{
+		StatusCode: statusCode,
+		Messages:   messages,
+		RequestID:  requestID,
+		kind:       kind,
+	}
+}
+
+func requestID(res *http.Response) string {
+	for _, header := range requestIDHeaders {
+		if value := res.Header.Get(header); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func kindForStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return ErrNotFound
+	case statusCode == http.StatusConflict:
+		return ErrConflict
+	case statusCode == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= 500:
+		return ErrServer
+	default:
+		return ErrValidation
+	}
+}
+
+// kindForMessages classifies envelope messages the API uses in place of a status code.
+func kindForMessages(messages []string) error {
+	for _, message := range messages {
+		lower := strings.ToLower(message)
+		switch {
+		case strings.Contains(lower, "not found"):
+			return ErrNotFound
+		case strings.Contains(lower, "already exists"), strings.Contains(lower, "already in use"):
+			return ErrConflict
+		}
+	}
+	return nil
+}
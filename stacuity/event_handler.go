@@ -3,152 +3,72 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
+	"iter"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetEventHandlers - Returns list of EventHandlers
-func (c *Client) GetEventHandlers(pagingState models.PagingState) ([]models.EventHandlerReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	EventHandlerItems := []models.EventHandlerReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EventHandlers?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return EventHandlerItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return EventHandlerItems, err
-	}
+// eventHandlers - Returns the collection backing the EventHandler methods
+func (c *Client) eventHandlers() Collection[models.EventHandlerReadItem, models.EventHandlerModifyItem] {
+	return NewCollection[models.EventHandlerReadItem, models.EventHandlerModifyItem](c, "EventHandlers")
+}
 
-	apiResponse := models.EventHandlerList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return EventHandlerItems, err
-	}
+// GetEventHandlers - Returns list of EventHandlers
+func (c *Client) GetEventHandlers(ctx context.Context, pagingState models.PagingState) ([]models.EventHandlerReadItem, error) {
+	return c.eventHandlers().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return EventHandlerItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetEventHandlersPage - Returns a single page of EventHandlers along with the paging totals
+func (c *Client) GetEventHandlersPage(ctx context.Context, pagingState models.PagingState) (models.EventHandlerList, error) {
+	return c.eventHandlers().ListPage(ctx, pagingState)
+}
 
-	EventHandlerItems = append(EventHandlerItems, apiResponse.Data...)
+// AllEventHandlers - Iterates over all EventHandlers matching pagingState, fetching pages as needed
+func (c *Client) AllEventHandlers(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.EventHandlerReadItem, error] {
+	return c.eventHandlers().All(ctx, pagingState)
+}
 
-	return EventHandlerItems, nil
+// GetAllEventHandlers - Returns all EventHandlers matching pagingState across all pages
+func (c *Client) GetAllEventHandlers(ctx context.Context, pagingState models.PagingState) ([]models.EventHandlerReadItem, error) {
+	return c.eventHandlers().ListAll(ctx, pagingState)
 }
 
 // GetEventHandler - Returns a specific EventHandler
-func (c *Client) GetEventHandler(EventHandlerId string) (models.EventHandlerReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EventHandlers/%s", c.HostURL, EventHandlerId), nil)
-	if err != nil {
-		return models.EventHandlerReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.EventHandlerReadItem{}, err
-	}
-
-	apiResponse := models.EventHandlerSingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.EventHandlerReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.EventHandlerReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return apiResponse.Data, nil
+func (c *Client) GetEventHandler(ctx context.Context, EventHandlerId string) (models.EventHandlerReadItem, error) {
+	item, version, err := c.eventHandlers().GetWithVersion(ctx, EventHandlerId)
+	item.Version = version
+	return item, err
 }
 
-// CreateEventHandler - Create a new Event Handler
-func (c *Client) CreateEventHandler(EventHandler models.EventHandlerModifyItem) (*models.EventHandlerResponse, error) {
-	rb, err := json.Marshal(EventHandler)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/EventHandlers", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EventHandlerResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// CreateEventHandler - Create a new Event Handler, adopting an existing handler with the same moniker and configuration
+func (c *Client) CreateEventHandler(ctx context.Context, EventHandler models.EventHandlerModifyItem) (*models.EventHandlerResponse, error) {
+	return c.eventHandlers().CreateOrAdopt(ctx, EventHandler, EventHandler.Moniker, eventHandlerMatches, func(existing models.EventHandlerReadItem) string {
+		return existing.Id
+	})
 }
 
 // UpdateEventHandler - Update a new Event Handler
-func (c *Client) UpdateEventHandler(EventHandlerId string, EventHandler models.EventHandlerModifyItem) (*models.EventHandlerResponse, error) {
-	rb, err := json.Marshal(EventHandler)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/EventHandlers/%s", c.HostURL, EventHandlerId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EventHandlerResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateEventHandler(ctx context.Context, EventHandlerId string, EventHandler models.EventHandlerModifyItem, version string) (*models.EventHandlerResponse, error) {
+	return c.eventHandlers().Update(ctx, EventHandlerId, EventHandler, version)
 }
 
 // DeleteEventHandler - Delete a EventHandler
-func (c *Client) DeleteEventHandler(EventHandlerId string) (*models.EventHandlerResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/EventHandlers/%s", c.HostURL, EventHandlerId), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EventHandlerResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) DeleteEventHandler(ctx context.Context, EventHandlerId string) (*models.EventHandlerResponse, error) {
+	return c.eventHandlers().Delete(ctx, EventHandlerId)
+}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
+// eventHandlerMatches reports whether an existing event handler has the configuration requested
+// by want. Webhook credentials are not returned by the API, so only the webhook URL is compared.
+func eventHandlerMatches(existing models.EventHandlerReadItem, want models.EventHandlerModifyItem) bool {
+	webhookMatches := want.ConfigurationData.WebhookConfig == nil && existing.ConfigurationData.WebhookConfig == nil
+	if want.ConfigurationData.WebhookConfig != nil && existing.ConfigurationData.WebhookConfig != nil {
+		webhookMatches = existing.ConfigurationData.WebhookConfig.Url == want.ConfigurationData.WebhookConfig.Url
 	}
 
-	return &apiResponse, nil
+	return existing.Name == want.Name &&
+		existing.EventEndpointType.Moniker == want.EventEndpointType &&
+		webhookMatches
 }
@@ -3,210 +3,102 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
+	"iter"
 	"net/http"
-	"strings"
+	"net/url"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetEventMaps - Returns list of EventMaps
-func (c *Client) GetEventMaps(pagingState models.PagingState) ([]models.EventMapReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	EventMapItems := []models.EventMapReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EventMaps?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return EventMapItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return EventMapItems, err
-	}
+// eventMaps - Returns the collection backing the EventMap methods
+func (c *Client) eventMaps() Collection[models.EventMapReadItem, models.EventMapModifyItem] {
+	return NewCollection[models.EventMapReadItem, models.EventMapModifyItem](c, "EventMaps")
+}
 
-	apiResponse := models.EventMapList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return EventMapItems, err
-	}
+// GetEventMaps - Returns list of EventMaps
+func (c *Client) GetEventMaps(ctx context.Context, pagingState models.PagingState) ([]models.EventMapReadItem, error) {
+	return c.eventMaps().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return EventMapItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetEventMapsPage - Returns a single page of EventMaps along with the paging totals
+func (c *Client) GetEventMapsPage(ctx context.Context, pagingState models.PagingState) (models.EventMapList, error) {
+	return c.eventMaps().ListPage(ctx, pagingState)
+}
 
-	EventMapItems = append(EventMapItems, apiResponse.Data...)
+// AllEventMaps - Iterates over all EventMaps matching pagingState, fetching pages as needed
+func (c *Client) AllEventMaps(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.EventMapReadItem, error] {
+	return c.eventMaps().All(ctx, pagingState)
+}
 
-	return EventMapItems, nil
+// GetAllEventMaps - Returns all EventMaps matching pagingState across all pages
+func (c *Client) GetAllEventMaps(ctx context.Context, pagingState models.PagingState) ([]models.EventMapReadItem, error) {
+	return c.eventMaps().ListAll(ctx, pagingState)
 }
 
 // GetEventMap - Returns a specific EventMap
-func (c *Client) GetEventMap(EventMapId string) (models.EventMapReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EventMaps/%s", c.HostURL, EventMapId), nil)
-	if err != nil {
-		return models.EventMapReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.EventMapReadItem{}, err
-	}
-
-	apiResponse := models.EventMapSingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.EventMapReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.EventMapReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return apiResponse.Data, nil
+func (c *Client) GetEventMap(ctx context.Context, EventMapId string) (models.EventMapReadItem, error) {
+	item, version, err := c.eventMaps().GetWithVersion(ctx, EventMapId)
+	item.Version = version
+	return item, err
 }
 
 // GetEventMapSubscriptions - Returns a specific EventMap subscriptions
-func (c *Client) GetEventMapSubscriptions(EventMapId string) ([]models.Subscription, error) {
-	eventMapSubscriptionItems := []models.Subscription{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/EventMaps/%s/subscriptions", c.HostURL, EventMapId), nil)
-	if err != nil {
-		return eventMapSubscriptionItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return eventMapSubscriptionItems, err
-	}
-
+func (c *Client) GetEventMapSubscriptions(ctx context.Context, EventMapId string) ([]models.Subscription, error) {
 	apiResponse := models.SubscriptionList{}
-	err = json.Unmarshal(body, &apiResponse)
+	err := c.send(ctx, http.MethodGet, c.eventMaps().ItemPath(EventMapId, "subscriptions"), nil, &apiResponse)
 	if err != nil {
-		return eventMapSubscriptionItems, err
+		return []models.Subscription{}, err
 	}
 
-	if !apiResponse.Success {
-		return eventMapSubscriptionItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	eventMapSubscriptionItems = append(eventMapSubscriptionItems, apiResponse.Data...)
-
-	return eventMapSubscriptionItems, nil
+	return append([]models.Subscription{}, apiResponse.Data...), nil
 }
 
-// CreateEventMap - Create a new Event Map
-func (c *Client) CreateEventMap(EventMap models.EventMapModifyItem) (*models.EventMapResponse, error) {
-	rb, err := json.Marshal(EventMap)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/EventMaps", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EventMapResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// CreateEventMap - Create a new Event Map, adopting an existing map with the same moniker and configuration
+func (c *Client) CreateEventMap(ctx context.Context, EventMap models.EventMapModifyItem) (*models.EventMapResponse, error) {
+	return c.eventMaps().CreateOrAdopt(ctx, EventMap, EventMap.Moniker, eventMapMatches, func(existing models.EventMapReadItem) string {
+		return existing.Id
+	})
 }
 
 // AddEventMapSubscriptions - add new Event Map subscriptions
-func (c *Client) AddEventMapSubscriptions(EventMapSubscription []models.EventMapSubscriptionModifyItem, EventMapId string) (*models.SubscriptionResponse, error) {
-	rb, err := json.Marshal(EventMapSubscription)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/EventMaps/%s/subscriptions", c.HostURL, EventMapId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Client) AddEventMapSubscriptions(ctx context.Context, EventMapSubscription []models.EventMapSubscriptionModifyItem, EventMapId string) (*models.SubscriptionResponse, error) {
 	apiResponse := models.SubscriptionResponse{}
-	err = json.Unmarshal(body, &apiResponse)
+	err := c.send(ctx, http.MethodPost, c.eventMaps().ItemPath(EventMapId, "subscriptions"), EventMapSubscription, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
 	return &apiResponse, nil
 }
 
-// UpdateEventMap - Update a new Event Map
-func (c *Client) UpdateEventMap(EventMapId string, EventMap models.EventMapModifyItem) (*models.EventMapResponse, error) {
-	rb, err := json.Marshal(EventMap)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/EventMaps/%s", c.HostURL, EventMapId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
+// DeleteEventMapSubscription - Remove a single Event Map subscription
+func (c *Client) DeleteEventMapSubscription(ctx context.Context, EventMapId string, SubscriptionId string) (*models.SubscriptionDeleteResponse, error) {
+	apiResponse := models.SubscriptionDeleteResponse{}
+	err := c.send(ctx, http.MethodDelete, c.eventMaps().ItemPath(EventMapId, "subscriptions/"+url.PathEscape(SubscriptionId)), nil, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	apiResponse := models.EventMapResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
 	return &apiResponse, nil
 }
 
-// DeleteEventMap - Delete a Event Map
-func (c *Client) DeleteEventMap(EventMapId string) (*models.EventMapResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/EventMaps/%s", c.HostURL, EventMapId), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.EventMapResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
+// UpdateEventMap - Update a new Event Map
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateEventMap(ctx context.Context, EventMapId string, EventMap models.EventMapModifyItem, version string) (*models.EventMapResponse, error) {
+	return c.eventMaps().Update(ctx, EventMapId, EventMap, version)
+}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// DeleteEventMap - Delete a Event Map
+func (c *Client) DeleteEventMap(ctx context.Context, EventMapId string) (*models.EventMapResponse, error) {
+	return c.eventMaps().Delete(ctx, EventMapId)
+}
 
-	return &apiResponse, nil
+// eventMapMatches reports whether an existing event map has the configuration requested by want.
+// Subscriptions are added after the map is created, so a map that already has some is not adopted.
+func eventMapMatches(existing models.EventMapReadItem, want models.EventMapModifyItem) bool {
+	return existing.Name == want.Name &&
+		existing.EventScope.Moniker == want.EventScope &&
+		(existing.Subscriptions == nil || len(*existing.Subscriptions) == 0)
 }
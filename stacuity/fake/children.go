@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+
+package fake
+
+import (
+	"net/http"
+
+	"stacuity.com/go_client/models"
+)
+
+func (s *Server) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	eventMap, _ := s.eventMaps.find(r.PathValue("id"))
+	if eventMap == nil {
+		writeNotFound(w)
+		return
+	}
+
+	writeList(w, s.subscriptions[eventMap.id])
+}
+
+func (s *Server) addSubscriptions(w http.ResponseWriter, r *http.Request) {
+	eventMap, _ := s.eventMaps.find(r.PathValue("id"))
+	if eventMap == nil {
+		writeNotFound(w)
+		return
+	}
+
+	var in []models.EventMapSubscriptionModifyItem
+	if !decode(w, r, &in) {
+		return
+	}
+
+	// Validate the whole request before storing anything, as the API does.
+	for _, sub := range in {
+		if handler, _ := s.eventHandlers.find(sub.EventEndpointId); handler == nil {
+			writeError(w, http.StatusBadRequest, "Unknown event handler "+sub.EventEndpointId)
+			return
+		}
+		if sub.EventTypeId == "" {
+			writeError(w, http.StatusBadRequest, "Event type is required")
+			return
+		}
+	}
+
+	response := models.SubscriptionResponse{Success: true, Messages: []string{}, Data: []models.SubscriptionResponseItem{}}
+	for _, sub := range in {
+		handler, _ := s.eventHandlers.find(sub.EventEndpointId)
+		eventType := models.EventType{Moniker: sub.EventTypeId, Name: sub.EventTypeId, Active: true}
+
+		created := models.Subscription{
+			Id:        s.newID(),
+			EventMap:  models.EventMap{Id: eventMap.id, Moniker: eventMap.moniker, Name: eventMap.name},
+			EventType: eventType,
+			EventEndpoint: models.EventEndpoint{
+				Id:                 handler.id,
+				Name:               handler.name,
+				Moniker:            handler.moniker,
+				Type:               handler.item.EventEndpointType.Moniker,
+				Active:             true,
+				SummaryDescription: handler.item.SummaryDescription,
+			},
+		}
+		s.subscriptions[eventMap.id] = append(s.subscriptions[eventMap.id], created)
+
+		response.Data = append(response.Data, models.SubscriptionResponseItem{
+			EventSubscriptionId: created.Id,
+			EventEndpointId:     handler.id,
+			EventType:           eventType,
+			EventHandler:        models.EventHandler{Id: handler.id, Moniker: handler.moniker, Name: handler.name},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	eventMap, _ := s.eventMaps.find(r.PathValue("id"))
+	if eventMap == nil {
+		writeNotFound(w)
+		return
+	}
+
+	subscriptions := s.subscriptions[eventMap.id]
+	index := entryIndex(subscriptions, r.PathValue("subscriptionId"), func(sub models.Subscription) string { return sub.Id })
+	if index < 0 {
+		writeNotFound(w)
+		return
+	}
+
+	s.subscriptions[eventMap.id] = append(subscriptions[:index], subscriptions[index+1:]...)
+	writeResult(w, r.PathValue("subscriptionId"))
+}
+
+func (s *Server) listOperatorEntries(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.operatorPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	writeList(w, s.operatorEntries[policy.id])
+}
+
+func (s *Server) addOperatorEntries(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.operatorPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	var in []models.OperatorPolicyEntryModifyItem
+	if !decode(w, r, &in) {
+		return
+	}
+
+	for _, entry := range in {
+		s.operatorEntries[policy.id] = append(s.operatorEntries[policy.id], models.OperatorPolicyEntry{
+			Id:         s.newID(),
+			OperatorId: entry.OperatorId,
+			Iso3:       entry.Iso3,
+			SteeringProfileEntryAction: models.SteeringProfileEntryAction{
+				Moniker: entry.SteeringProfileEntryAction,
+				Name:    entry.SteeringProfileEntryAction,
+				Active:  true,
+			},
+		})
+	}
+
+	writeResult(w, policy.moniker)
+}
+
+func (s *Server) updateOperatorEntry(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.operatorPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	index := entryIndex(s.operatorEntries[policy.id], r.PathValue("entryId"), func(entry models.OperatorPolicyEntry) string { return entry.Id })
+	if index < 0 {
+		writeNotFound(w)
+		return
+	}
+
+	var in models.OperatorPolicyEntryModifyItem
+	if !decode(w, r, &in) {
+		return
+	}
+
+	entry := &s.operatorEntries[policy.id][index]
+	entry.OperatorId = in.OperatorId
+	entry.Iso3 = in.Iso3
+	entry.SteeringProfileEntryAction = models.SteeringProfileEntryAction{
+		Moniker: in.SteeringProfileEntryAction,
+		Name:    in.SteeringProfileEntryAction,
+		Active:  true,
+	}
+
+	writeResult(w, entry.Id)
+}
+
+func (s *Server) deleteOperatorEntry(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.operatorPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	entries := s.operatorEntries[policy.id]
+	index := entryIndex(entries, r.PathValue("entryId"), func(entry models.OperatorPolicyEntry) string { return entry.Id })
+	if index < 0 {
+		writeNotFound(w)
+		return
+	}
+
+	s.operatorEntries[policy.id] = append(entries[:index], entries[index+1:]...)
+	writeResult(w, r.PathValue("entryId"))
+}
+
+func (s *Server) listRegionalEntries(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.regionalPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	writeList(w, s.regionalEntries[policy.id])
+}
+
+// addRegionalEntry stores a single entry; the regional policy API does not accept arrays.
+func (s *Server) addRegionalEntry(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.regionalPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	var in models.RegionalPolicyEntryModifyItem
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.RegionalGatewayId == nil || *in.RegionalGatewayId == "" {
+		writeError(w, http.StatusBadRequest, "Regional gateway is required")
+		return
+	}
+
+	policyID := policy.id
+	entry := models.RegionalPolicyEntry{
+		Id:                      s.newID(),
+		OperatorId:              in.OperatorId,
+		RegionalGatewayPolicyId: &policyID,
+		Iso3:                    in.Iso3,
+	}
+	entry.RegionalGateway.Moniker = *in.RegionalGatewayId
+	entry.RegionalGateway.Name = *in.RegionalGatewayId
+
+	s.regionalEntries[policy.id] = append(s.regionalEntries[policy.id], entry)
+	writeResult(w, entry.Id)
+}
+
+func (s *Server) updateRegionalEntry(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.regionalPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	index := entryIndex(s.regionalEntries[policy.id], r.PathValue("entryId"), func(entry models.RegionalPolicyEntry) string { return entry.Id })
+	if index < 0 {
+		writeNotFound(w)
+		return
+	}
+
+	var in models.RegionalPolicyEntryModifyItem
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.RegionalGatewayId == nil || *in.RegionalGatewayId == "" {
+		writeError(w, http.StatusBadRequest, "Regional gateway is required")
+		return
+	}
+
+	entry := &s.regionalEntries[policy.id][index]
+	entry.OperatorId = in.OperatorId
+	entry.Iso3 = in.Iso3
+	entry.RegionalGateway.Moniker = *in.RegionalGatewayId
+	entry.RegionalGateway.Name = *in.RegionalGatewayId
+
+	writeResult(w, entry.Id)
+}
+
+func (s *Server) deleteRegionalEntry(w http.ResponseWriter, r *http.Request) {
+	policy, _ := s.regionalPolicies.find(r.PathValue("id"))
+	if policy == nil {
+		writeNotFound(w)
+		return
+	}
+
+	entries := s.regionalEntries[policy.id]
+	index := entryIndex(entries, r.PathValue("entryId"), func(entry models.RegionalPolicyEntry) string { return entry.Id })
+	if index < 0 {
+		writeNotFound(w)
+		return
+	}
+
+	s.regionalEntries[policy.id] = append(entries[:index], entries[index+1:]...)
+	writeResult(w, r.PathValue("entryId"))
+}
+
+// entryIndex returns the position of the child object with the given id, or -1.
+func entryIndex[T any](entries []T, id string, idOf func(T) string) int {
+	for i, entry := range entries {
+		if idOf(entry) == id {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+
+package fake
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	stacuity "stacuity.com/go_client"
+)
+
+// record - One stored object along with the identifiers it can be addressed by
+type record[T any] struct {
+	id      string
+	moniker string
+	name    string
+	item    T
+	// version increases on every update and is served as the ETag.
+	version int
+}
+
+func (rec *record[T]) etag() string {
+	return strconv.Quote(strconv.Itoa(rec.version))
+}
+
+// builder turns a create or update payload into the object the API would return for id.
+type builder[Read any, Modify any] func(id string, in Modify) record[Read]
+
+// collection - In-memory store for one object type, kept in creation order
+type collection[Read any, Modify any] struct {
+	path    string
+	records []*record[Read]
+	build   builder[Read, Modify]
+	// onDelete, when set, removes child objects of a deleted record.
+	onDelete func(id string)
+}
+
+func newCollection[Read any, Modify any](path string, build builder[Read, Modify]) *collection[Read, Modify] {
+	return &collection[Read, Modify]{path: path, build: build}
+}
+
+// find returns the record addressed by key, which may be either its id or its moniker.
+func (c *collection[Read, Modify]) find(key string) (*record[Read], int) {
+	for i, rec := range c.records {
+		if rec.id == key || rec.moniker == key {
+			return rec, i
+		}
+	}
+
+	return nil, -1
+}
+
+// register adds the list, get, create, update and delete routes for c.
+func register[Read any, Modify any](s *Server, c *collection[Read, Modify]) {
+	s.handle("GET /"+c.path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		filter := strings.ToLower(query.Get("filter"))
+
+		matched := []Read{}
+		for _, rec := range c.records {
+			if filter == "" || strings.Contains(strings.ToLower(rec.moniker), filter) || strings.Contains(strings.ToLower(rec.name), filter) {
+				matched = append(matched, rec.item)
+			}
+		}
+
+		total := len(matched)
+		offset = min(max(offset, 0), total)
+		end := total
+		if limit > 0 {
+			end = min(offset+limit, total)
+		}
+
+		page := matched[offset:end]
+		writeJSON(w, http.StatusOK, pageOf(page, total, limit, offset))
+	})
+
+	s.handle("GET /"+c.path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		rec, _ := c.find(r.PathValue("id"))
+		if rec == nil {
+			writeNotFound(w)
+			return
+		}
+
+		w.Header().Set("ETag", rec.etag())
+		writeSingle(w, rec.item)
+	})
+
+	s.handle("POST /"+c.path, func(w http.ResponseWriter, r *http.Request) {
+		// A repeated create answers with the original result instead of a moniker clash.
+		key := r.Header.Get(stacuity.IdempotencyKeyHeader)
+		if data, ok := s.idempotent[key]; ok && key != "" {
+			writeResult(w, data)
+			return
+		}
+
+		var in Modify
+		if !decode(w, r, &in) {
+			return
+		}
+
+		rec := c.build(s.newID(), in)
+		if rec.moniker == "" {
+			writeError(w, http.StatusBadRequest, "Moniker is required")
+			return
+		}
+		if existing, _ := c.find(rec.moniker); existing != nil {
+			writeError(w, http.StatusConflict, "Moniker "+rec.moniker+" already exists")
+			return
+		}
+
+		rec.version = 1
+		c.records = append(c.records, &rec)
+		if key != "" {
+			s.idempotent[key] = rec.id
+		}
+		writeResult(w, rec.id)
+	})
+
+	s.handle("PUT /"+c.path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		current, _ := c.find(r.PathValue("id"))
+		if current == nil {
+			writeNotFound(w)
+			return
+		}
+
+		if version := r.Header.Get("If-Match"); version != "" && version != current.etag() {
+			writeError(w, http.StatusPreconditionFailed, "The object has been changed since it was read")
+			return
+		}
+
+		var in Modify
+		if !decode(w, r, &in) {
+			return
+		}
+
+		rec := c.build(current.id, in)
+		if rec.moniker == "" {
+			writeError(w, http.StatusBadRequest, "Moniker is required")
+			return
+		}
+		if existing, _ := c.find(rec.moniker); existing != nil && existing != current {
+			writeError(w, http.StatusConflict, "Moniker "+rec.moniker+" already exists")
+			return
+		}
+
+		rec.version = current.version + 1
+		*current = rec
+		writeResult(w, rec.moniker)
+	})
+
+	s.handle("DELETE /"+c.path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		rec, index := c.find(r.PathValue("id"))
+		if rec == nil {
+			writeNotFound(w)
+			return
+		}
+
+		c.records = append(c.records[:index], c.records[index+1:]...)
+		if c.onDelete != nil {
+			c.onDelete(rec.id)
+		}
+
+		writeResult(w, rec.moniker)
+	})
+}
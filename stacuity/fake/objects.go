@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+
+package fake
+
+import (
+	"stacuity.com/go_client/models"
+)
+
+// ref resolves a moniker or id supplied in a payload to the referenced object. References to
+// objects the fake does not hold, such as platform-defined regional gateways, are echoed back.
+func ref[Read any, Modify any](c *collection[Read, Modify], key string) (id, moniker, name string) {
+	if rec, _ := c.find(key); rec != nil {
+		return rec.id, rec.moniker, rec.name
+	}
+
+	return "", key, key
+}
+
+func (s *Server) buildVSlice(id string, in models.VSliceModifyItem) record[models.VSliceReadItem] {
+	item := models.VSliceReadItem{
+		Id:              id,
+		Name:            in.Name,
+		Moniker:         in.Moniker,
+		Subnets:         []string{},
+		DNSServers:      append([]string{}, in.DNSServers...),
+		DNSMode:         models.DNSMode{Moniker: in.DNSMode, Name: in.DNSMode, Active: true},
+		IpAddressFamily: models.IpAddressFamily{Moniker: in.IpAddressFamily, Name: in.IpAddressFamily, Active: true},
+	}
+
+	if in.SubnetAddress != "" {
+		item.Subnets = append(item.Subnets, in.SubnetAddress)
+	}
+	if in.EventMap != "" {
+		item.EventMap.Id, item.EventMap.Moniker, item.EventMap.Name = ref(s.eventMaps, in.EventMap)
+	}
+
+	return record[models.VSliceReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildRoutingPolicy(id string, in models.RoutingPolicyModifyItem) record[models.RoutingPolicyReadItem] {
+	item := models.RoutingPolicyReadItem{
+		Id:                              id,
+		Name:                            in.Name,
+		Moniker:                         in.Moniker,
+		RateLimitUplink:                 models.RateLimit{Moniker: in.RateLimitUplinkMoniker, Name: in.RateLimitUplinkMoniker, Active: true},
+		RateLimitDownlink:               models.RateLimit{Moniker: in.RateLimitDownlinkMoniker, Name: in.RateLimitDownlinkMoniker, Active: true},
+		PacketDiscardUplinkPercentage:   in.PacketDiscardUplinkPercentage,
+		PacketDiscardDownlinkPercentage: in.PacketDiscardDownlinkPercentage,
+		RoutingPolicyStatus:             models.RoutingPolicy{Moniker: in.RoutingPolicyStatus, Name: in.RoutingPolicyStatus, Active: true},
+		RoutingPolicyRules:              &[]models.Rule{},
+		RoutingPolicyEdgeServices:       &[]models.EdgeService{},
+	}
+	item.VSlice.Id, item.VSlice.Moniker, item.VSlice.Name = ref(s.vSlices, in.VSlice)
+
+	for i, modify := range in.RoutingPolicyRules {
+		rule := models.Rule{
+			Id:                     s.newID(),
+			RoutingPolicyId:        id,
+			Description:            modify.Description,
+			RuleAction:             models.RuleAction{Moniker: modify.RuleAction, Name: modify.RuleAction, Active: true},
+			RuleDirection:          models.RuleDirection{Moniker: modify.RuleDirection, Name: modify.RuleDirection, Active: true},
+			Precedence:             int32(i + 1),
+			SourceIpPattern:        modify.SourceIpPattern,
+			DestinationIpPattern:   modify.DestinationIpPattern,
+			DivertIp:               modify.DivertIp,
+			DivertPort:             modify.DivertPort,
+			SourcePortPattern:      modify.SourcePortPattern,
+			DestinationPortPattern: modify.DestinationPortPattern,
+			Reflexive:              modify.Reflexive,
+			Enabled:                modify.Enabled,
+		}
+
+		if modify.TransportProtocol != nil {
+			rule.TransportProtocol = &models.TransportProtocol{Moniker: *modify.TransportProtocol, Name: *modify.TransportProtocol, Active: true}
+		}
+		if modify.RoutingTarget != nil {
+			rule.RoutingTarget = &models.RoutingTarget{}
+			rule.RoutingTarget.Id, rule.RoutingTarget.Moniker, rule.RoutingTarget.Name = ref(s.routingTargets, *modify.RoutingTarget)
+		}
+		if modify.RegionalGateway != nil {
+			rule.RegionalGateway = &models.RegionalGateway{Moniker: *modify.RegionalGateway, Name: *modify.RegionalGateway}
+		}
+
+		*item.RoutingPolicyRules = append(*item.RoutingPolicyRules, rule)
+	}
+
+	*item.RoutingPolicyEdgeServices = append(*item.RoutingPolicyEdgeServices, in.RoutingPolicyEdgeServices...)
+
+	return record[models.RoutingPolicyReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildRoutingTarget(id string, in models.RoutingTargetModifyItem) record[models.RoutingTargetReadItem] {
+	item := models.RoutingTargetReadItem{
+		Id:                           id,
+		Name:                         in.Name,
+		Moniker:                      in.Moniker,
+		RoutingTargetType:            models.RoutingTargetType{Moniker: in.RoutingTargetType, Name: in.RoutingTargetType, Active: true},
+		RoutingTargetStatus:          models.RoutingTargetStatus{Moniker: "active", Name: "Active", Active: true},
+		ConfigurationData:            in.ConfigurationData,
+		RoutingTargetTypeInstance:    models.RoutingTargetTypeInstance{Moniker: in.RoutingTargetTypeInstanceId, Name: in.RoutingTargetTypeInstanceId},
+		RoutingRedundancyZoneMoniker: in.RoutingRedundancyZoneMoniker,
+		RoutingRedundancyZoneName:    in.RoutingRedundancyZoneMoniker,
+	}
+	item.VSlice.Id, item.VSlice.Moniker, item.VSlice.Name = ref(s.vSlices, in.VSlice)
+
+	return record[models.RoutingTargetReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildEndpointGroup(id string, in models.EndpointGroupModifyItem) record[models.EndpointGroupReadItem] {
+	item := models.EndpointGroupReadItem{
+		Id:               id,
+		Name:             in.Name,
+		Moniker:          in.Moniker,
+		IPAllocationType: models.IPAllocationType{Moniker: in.IPAllocationType, Name: in.IPAllocationType, Active: true},
+	}
+	item.VSlice.Id, item.VSlice.Moniker, item.VSlice.Name = ref(s.vSlices, in.VSlice)
+	item.RegionalGatewayPolicy.Id, item.RegionalGatewayPolicy.Moniker, item.RegionalGatewayPolicy.Name = ref(s.regionalPolicies, in.RegionalGatewayPolicy)
+
+	if in.EventMap != nil {
+		item.EventMap = &models.EventMap{}
+		item.EventMap.Id, item.EventMap.Moniker, item.EventMap.Name = ref(s.eventMaps, *in.EventMap)
+	}
+	if in.RoutingPolicy != nil {
+		item.RoutingPolicy = &models.RoutingPolicy{Moniker: *in.RoutingPolicy, Active: true}
+		_, _, item.RoutingPolicy.Name = ref(s.routingPolicies, *in.RoutingPolicy)
+	}
+	if in.SteeringProfile != nil {
+		item.SteeringProfile = &models.SteeringProfile{}
+		item.SteeringProfile.Id, item.SteeringProfile.Moniker, item.SteeringProfile.Name = ref(s.operatorPolicies, *in.SteeringProfile)
+	}
+
+	return record[models.EndpointGroupReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildEventMap(id string, in models.EventMapModifyItem) record[models.EventMapReadItem] {
+	// Subscriptions are managed through the subscriptions child route rather than the map itself.
+	item := models.EventMapReadItem{
+		Id:         id,
+		Name:       in.Name,
+		Moniker:    in.Moniker,
+		EventScope: models.EventScope{Moniker: in.EventScope, Name: in.EventScope, Active: true},
+	}
+
+	return record[models.EventMapReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildEventHandler(id string, in models.EventHandlerModifyItem) record[models.EventHandlerReadItem] {
+	item := models.EventHandlerReadItem{
+		Id:                id,
+		Name:              in.Name,
+		Moniker:           in.Moniker,
+		ConfigurationData: in.ConfigurationData,
+		EventEndpointType: models.EventEndpoint{Moniker: in.EventEndpointType, Name: in.EventEndpointType, Active: true},
+	}
+
+	if in.ConfigurationData.WebhookConfig != nil {
+		item.SummaryDescription = in.ConfigurationData.WebhookConfig.Url
+	}
+
+	return record[models.EventHandlerReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildOperatorPolicy(id string, in models.OperatorPolicyModifyItem) record[models.OperatorPolicyReadItem] {
+	// Entries are managed through the entries child route rather than the policy itself.
+	item := models.OperatorPolicyReadItem{
+		Id:       id,
+		Name:     in.Name,
+		Moniker:  in.Moniker,
+		Allow2g:  true,
+		Allow3g:  true,
+		Allow45g: true,
+	}
+
+	return record[models.OperatorPolicyReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
+
+func (s *Server) buildRegionalPolicy(id string, in models.RegionalPolicyModifyItem) record[models.RegionalPolicyReadItem] {
+	// Entries are managed through the entries child route rather than the policy itself.
+	item := models.RegionalPolicyReadItem{
+		Id:      id,
+		Name:    in.Name,
+		Moniker: in.Moniker,
+		Active:  true,
+	}
+
+	return record[models.RegionalPolicyReadItem]{id: id, moniker: in.Moniker, name: in.Name, item: item}
+}
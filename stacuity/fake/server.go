@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package fake provides an in-memory, stateful stand-in for the Stacuity REST API so the
+// client and the Terraform provider can be exercised without a Stacuity account.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	stacuity "stacuity.com/go_client"
+	"stacuity.com/go_client/models"
+)
+
+// BasePath - Path prefix the fake API is served under, matching stacuity.HostURL
+const BasePath = "/api/v1"
+
+// Token - Bearer token accepted by the fake API
+const Token = "fake-token"
+
+// Server - Fake Stacuity API. Objects live in memory for the lifetime of the server and
+// every request is served under a single lock, so the server is safe for parallel callers.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	mux      *http.ServeMux
+	nextID   int
+	requests int
+
+	vSlices          *collection[models.VSliceReadItem, models.VSliceModifyItem]
+	routingPolicies  *collection[models.RoutingPolicyReadItem, models.RoutingPolicyModifyItem]
+	routingTargets   *collection[models.RoutingTargetReadItem, models.RoutingTargetModifyItem]
+	endpointGroups   *collection[models.EndpointGroupReadItem, models.EndpointGroupModifyItem]
+	eventMaps        *collection[models.EventMapReadItem, models.EventMapModifyItem]
+	eventHandlers    *collection[models.EventHandlerReadItem, models.EventHandlerModifyItem]
+	operatorPolicies *collection[models.OperatorPolicyReadItem, models.OperatorPolicyModifyItem]
+	regionalPolicies *collection[models.RegionalPolicyReadItem, models.RegionalPolicyModifyItem]
+
+	// Results of creates, keyed by the Idempotency-Key they were sent with.
+	idempotent map[string]string
+
+	// Child objects, keyed by the id of their parent.
+	subscriptions   map[string][]models.Subscription
+	operatorEntries map[string][]models.OperatorPolicyEntry
+	regionalEntries map[string][]models.RegionalPolicyEntry
+}
+
+// NewServer - Starts a fake API server. Callers must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		mux:             http.NewServeMux(),
+		idempotent:      map[string]string{},
+		subscriptions:   map[string][]models.Subscription{},
+		operatorEntries: map[string][]models.OperatorPolicyEntry{},
+		regionalEntries: map[string][]models.RegionalPolicyEntry{},
+	}
+
+	s.vSlices = newCollection("vslices", s.buildVSlice)
+	s.routingPolicies = newCollection("RoutingPolicies", s.buildRoutingPolicy)
+	s.routingTargets = newCollection("routingtargets", s.buildRoutingTarget)
+	s.endpointGroups = newCollection("EndpointGroups", s.buildEndpointGroup)
+	s.eventMaps = newCollection("EventMaps", s.buildEventMap)
+	s.eventHandlers = newCollection("EventHandlers", s.buildEventHandler)
+	s.operatorPolicies = newCollection("OperatorPolicies", s.buildOperatorPolicy)
+	s.regionalPolicies = newCollection("RegionalPolicies", s.buildRegionalPolicy)
+
+	s.eventMaps.onDelete = func(id string) { delete(s.subscriptions, id) }
+	s.operatorPolicies.onDelete = func(id string) { delete(s.operatorEntries, id) }
+	s.regionalPolicies.onDelete = func(id string) { delete(s.regionalEntries, id) }
+
+	register(s, s.vSlices)
+	register(s, s.routingPolicies)
+	register(s, s.routingTargets)
+	register(s, s.endpointGroups)
+	register(s, s.eventMaps)
+	register(s, s.eventHandlers)
+	register(s, s.operatorPolicies)
+	register(s, s.regionalPolicies)
+
+	s.handle("GET /EventMaps/{id}/subscriptions", s.listSubscriptions)
+	s.handle("POST /EventMaps/{id}/subscriptions", s.addSubscriptions)
+	s.handle("DELETE /EventMaps/{id}/subscriptions/{subscriptionId}", s.deleteSubscription)
+	s.handle("GET /OperatorPolicies/{id}/entries", s.listOperatorEntries)
+	s.handle("POST /OperatorPolicies/{id}/entries", s.addOperatorEntries)
+	s.handle("PUT /OperatorPolicies/{id}/entries/{entryId}", s.updateOperatorEntry)
+	s.handle("DELETE /OperatorPolicies/{id}/entries/{entryId}", s.deleteOperatorEntry)
+	s.handle("GET /RegionalPolicies/{id}/entries", s.listRegionalEntries)
+	s.handle("POST /RegionalPolicies/{id}/entries", s.addRegionalEntry)
+	s.handle("PUT /RegionalPolicies/{id}/entries/{entryId}", s.updateRegionalEntry)
+	s.handle("DELETE /RegionalPolicies/{id}/entries/{entryId}", s.deleteRegionalEntry)
+
+	s.Server = httptest.NewServer(s.mux)
+	return s
+}
+
+// HostURL - Returns the value to use for the provider's host attribute
+func (s *Server) HostURL() string {
+	return s.URL + BasePath
+}
+
+// APIClient - Returns a client pointed at the fake server and authenticated with Token
+func (s *Server) APIClient() *stacuity.Client {
+	host := s.HostURL()
+	token := Token
+	c, _ := stacuity.NewClient(&host, &token)
+	c.HTTPClient = s.Client()
+	return c
+}
+
+// handle registers an authenticated handler for pattern, relative to BasePath. The server
+// lock is held while the handler runs.
+func (s *Server) handle(pattern string, handler http.HandlerFunc) {
+	method, path, _ := strings.Cut(pattern, " ")
+	s.mux.HandleFunc(method+" "+BasePath+path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+Token {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.requests++
+		w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%d", s.requests))
+		handler(w, r)
+	})
+}
+
+// newID returns a unique, stable identifier in the UUID format used by the API.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+// decode reads a JSON request body into out, writing a validation error on failure.
+func decode(w http.ResponseWriter, r *http.Request, out any) bool {
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, messages ...string) {
+	writeJSON(w, status, models.Result{Success: false, Messages: messages})
+}
+
+func writeResult(w http.ResponseWriter, data string) {
+	writeJSON(w, http.StatusOK, models.Result{Success: true, Messages: []string{}, Data: data})
+}
+
+func writeSingle[T any](w http.ResponseWriter, data T) {
+	writeJSON(w, http.StatusOK, models.Single[T]{Success: true, Messages: []string{}, Data: data})
+}
+
+func writeList[T any](w http.ResponseWriter, data []T) {
+	writeJSON(w, http.StatusOK, pageOf(data, len(data), len(data), 0))
+}
+
+// pageOf wraps one page of a list response in the API envelope.
+func pageOf[T any](data []T, total, limit, offset int) models.Page[T] {
+	return models.Page[T]{
+		Success:    true,
+		Messages:   []string{},
+		TotalItems: int32(total),
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+		Data:       append([]T{}, data...),
+	}
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "Record not found")
+}
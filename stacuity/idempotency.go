@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"slices"
+)
+
+// IdempotencyKeyHeader - Header carrying the key that lets the API recognise a repeated create
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContext struct{}
+
+// NewIdempotencyKey - Returns a random key identifying one logical create
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	// Format as a version 4 UUID.
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// WithIdempotencyKey - Returns a context whose create calls carry key in the Idempotency-Key
+// header. Every attempt of the create, including retries, sends the same key, which also makes
+// the POST safe to retry. Only Create calls use the key; reads and child-object calls made with
+// the same context do not.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+// idempotencyKey returns the key set by WithIdempotencyKey, if any.
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContext{}).(string)
+	return key
+}
+
+// refMatches reports whether a reference given as a moniker or an id in a payload points at
+// the object the API returned.
+func refMatches(id, moniker, want string) bool {
+	return want == moniker || (id != "" && want == id)
+}
+
+// equalPtr reports whether two optional values are both unset or both set to the same value.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// sameElements reports whether two lists hold the same values, ignoring order.
+func sameElements(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
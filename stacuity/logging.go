@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem - tflog subsystem used for HTTP request/response logs. Its level can be set
+// independently with TF_LOG_PROVIDER_STACUITY_API.
+const LogSubsystem = "stacuity_api"
+
+// redacted - Replacement for secret values in logs
+const redacted = "***"
+
+// secretFields - JSON keys whose values are never logged, compared case-insensitively
+var secretFields = map[string]bool{
+	"presharedkey":  true,
+	"password":      true,
+	"bearertoken":   true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"client_secret": true,
+	"clientsecret":  true,
+	"privatekey":    true,
+}
+
+// secretHeaders - Headers whose values are never logged
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// logRoundTrip writes one attempt of an API call to the stacuity_api subsystem at debug level.
+func logRoundTrip(req *http.Request, attempt int, latency time.Duration, res *http.Response, body []byte, err error) {
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_STACUITY_API"))
+
+	fields := map[string]interface{}{
+		"method":          req.Method,
+		"url":             req.URL.String(),
+		"attempt":         attempt,
+		"latency_ms":      latency.Milliseconds(),
+		"request_headers": redactHeaders(req.Header),
+	}
+
+	if req.GetBody != nil {
+		if reader, getErr := req.GetBody(); getErr == nil {
+			requestBody, _ := io.ReadAll(reader)
+			fields["request_body"] = redactBody(requestBody)
+		}
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	if res != nil {
+		fields["status"] = res.StatusCode
+		fields["response_headers"] = redactHeaders(res.Header)
+		fields["response_body"] = redactBody(body)
+		if id := requestID(res); id != "" {
+			fields["request_id"] = id
+		}
+	}
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Stacuity API request", fields)
+}
+
+// redactHeaders flattens headers for logging with secret values masked.
+func redactHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		flat[name] = strings.Join(values, ", ")
+	}
+
+	for _, name := range secretHeaders {
+		if value, ok := flat[http.CanonicalHeaderKey(name)]; ok {
+			if scheme, _, found := strings.Cut(value, " "); found && name == "Authorization" {
+				flat[http.CanonicalHeaderKey(name)] = scheme + " " + redacted
+			} else {
+				flat[http.CanonicalHeaderKey(name)] = redacted
+			}
+		}
+	}
+
+	return flat
+}
+
+// redactBody masks secret fields in a JSON or form-encoded body, such as an OAuth2 token
+// request. Other bodies are returned unchanged.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return redactForm(string(body))
+	}
+
+	masked, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(masked)
+}
+
+// redactForm masks secret fields in a form-encoded body. Text that has no secret keys,
+// including anything that is not a form at all, is returned unchanged.
+func redactForm(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+
+	found := false
+	for key := range values {
+		if secretFields[strings.ToLower(key)] {
+			values[key] = []string{redacted}
+			found = true
+		}
+	}
+
+	if !found {
+		return body
+	}
+
+	return values.Encode()
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if secretFields[strings.ToLower(key)] && child != nil {
+				typed[key] = redacted
+				continue
+			}
+			typed[key] = redactValue(child)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = redactValue(child)
+		}
+	}
+
+	return value
+}
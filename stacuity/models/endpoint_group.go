@@ -2,29 +2,11 @@
 
 package models
 
-type EndpointGroupList struct {
-	Success    bool                    `json:"success"`
-	Messages   []string                `json:"messages"`
-	TotalItems int32                   `json:"totalItems"`
-	Limit      int32                   `json:"limit"`
-	Offset     int32                   `json:"offset"`
-	Data       []EndpointGroupReadItem `json:"data"`
-}
+type EndpointGroupList = Page[EndpointGroupReadItem]
 
-type EndpointGroupSingle struct {
-	Success    bool                  `json:"success"`
-	Messages   []string              `json:"messages"`
-	TotalItems int32                 `json:"totalItems"`
-	Limit      int32                 `json:"limit"`
-	Offset     int32                 `json:"offset"`
-	Data       EndpointGroupReadItem `json:"data"`
-}
+type EndpointGroupSingle = Single[EndpointGroupReadItem]
 
-type EndpointGroupResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type EndpointGroupResponse = Result
 
 type EndpointGroupReadItem struct {
 	Id                    string                `json:"id,omitempty"`
@@ -38,6 +20,8 @@ type EndpointGroupReadItem struct {
 	RegionalGatewayPolicy RegionalGatewayPolicy `json:"regionalGatewayPolicy"`
 	IPAllocationType      IPAllocationType      `json:"ipAllocationType"`
 	CustomerId            string                `json:"customerId"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type SteeringProfile struct {
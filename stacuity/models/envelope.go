@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+
+package models
+
+// Page - Envelope returned by list endpoints
+type Page[T any] struct {
+	Success    bool     `json:"success"`
+	Messages   []string `json:"messages"`
+	TotalItems int32    `json:"totalItems"`
+	Limit      int32    `json:"limit"`
+	Offset     int32    `json:"offset"`
+	Data       []T      `json:"data"`
+}
+
+// Single - Envelope returned when reading one object
+type Single[T any] struct {
+	Success    bool     `json:"success"`
+	Messages   []string `json:"messages"`
+	TotalItems int32    `json:"totalItems"`
+	Limit      int32    `json:"limit"`
+	Offset     int32    `json:"offset"`
+	Data       T        `json:"data"`
+}
+
+// Result - Envelope returned by create, update and delete calls. Data holds the affected moniker
+// or id; a create returns the id of the new object.
+type Result struct {
+	Success  bool     `json:"success"`
+	Messages []string `json:"messages"`
+	Data     string   `json:"data"`
+	// Adopted is set by the client when a create found an existing matching object and adopted
+	// it instead. It is not part of the JSON body.
+	Adopted bool `json:"-"`
+}
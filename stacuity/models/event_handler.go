@@ -2,29 +2,11 @@
 
 package models
 
-type EventHandlerList struct {
-	Success    bool                   `json:"success"`
-	Messages   []string               `json:"messages"`
-	TotalItems int32                  `json:"totalItems"`
-	Limit      int32                  `json:"limit"`
-	Offset     int32                  `json:"offset"`
-	Data       []EventHandlerReadItem `json:"data"`
-}
+type EventHandlerList = Page[EventHandlerReadItem]
 
-type EventHandlerSingle struct {
-	Success    bool                 `json:"success"`
-	Messages   []string             `json:"messages"`
-	TotalItems int32                `json:"totalItems"`
-	Limit      int32                `json:"limit"`
-	Offset     int32                `json:"offset"`
-	Data       EventHandlerReadItem `json:"data"`
-}
+type EventHandlerSingle = Single[EventHandlerReadItem]
 
-type EventHandlerResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type EventHandlerResponse = Result
 
 type EventHandlerReadItem struct {
 	Id                 string        `json:"id,omitempty"`
@@ -33,6 +15,8 @@ type EventHandlerReadItem struct {
 	ConfigurationData  Configuration `json:"configurationData"`
 	SummaryDescription string        `json:"summaryDescription"`
 	EventEndpointType  EventEndpoint `json:"eventEndpointType"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type Configuration struct {
@@ -2,29 +2,11 @@
 
 package models
 
-type EventMapList struct {
-	Success    bool               `json:"success"`
-	Messages   []string           `json:"messages"`
-	TotalItems int32              `json:"totalItems"`
-	Limit      int32              `json:"limit"`
-	Offset     int32              `json:"offset"`
-	Data       []EventMapReadItem `json:"data"`
-}
+type EventMapList = Page[EventMapReadItem]
 
-type EventMapSingle struct {
-	Success    bool             `json:"success"`
-	Messages   []string         `json:"messages"`
-	TotalItems int32            `json:"totalItems"`
-	Limit      int32            `json:"limit"`
-	Offset     int32            `json:"offset"`
-	Data       EventMapReadItem `json:"data"`
-}
+type EventMapSingle = Single[EventMapReadItem]
 
-type EventMapResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type EventMapResponse = Result
 
 type EventMapReadItem struct {
 	Id            string          `json:"id,omitempty"`
@@ -32,6 +14,8 @@ type EventMapReadItem struct {
 	Name          string          `json:"name"`
 	EventScope    EventScope      `json:"eventScope"`
 	Subscriptions *[]Subscription `json:"subscriptions"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type Subscription struct {
@@ -76,14 +60,9 @@ type SubscriptionResponse struct {
 	Data     []SubscriptionResponseItem `json:"data"`
 }
 
-type SubscriptionList struct {
-	Success    bool           `json:"success"`
-	Messages   []string       `json:"messages"`
-	TotalItems int32          `json:"totalItems"`
-	Limit      int32          `json:"limit"`
-	Offset     int32          `json:"offset"`
-	Data       []Subscription `json:"data"`
-}
+type SubscriptionDeleteResponse = Result
+
+type SubscriptionList = Page[Subscription]
 
 type EventScope struct {
 	Key     int32  `json:"key"`
@@ -2,29 +2,11 @@
 
 package models
 
-type OperatorPolicyList struct {
-	Success    bool                     `json:"success"`
-	Messages   []string                 `json:"messages"`
-	TotalItems int32                    `json:"totalItems"`
-	Limit      int32                    `json:"limit"`
-	Offset     int32                    `json:"offset"`
-	Data       []OperatorPolicyReadItem `json:"data"`
-}
+type OperatorPolicyList = Page[OperatorPolicyReadItem]
 
-type OperatorPolicySingle struct {
-	Success    bool                   `json:"success"`
-	Messages   []string               `json:"messages"`
-	TotalItems int32                  `json:"totalItems"`
-	Limit      int32                  `json:"limit"`
-	Offset     int32                  `json:"offset"`
-	Data       OperatorPolicyReadItem `json:"data"`
-}
+type OperatorPolicySingle = Single[OperatorPolicyReadItem]
 
-type OperatorPolicyResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type OperatorPolicyResponse = Result
 
 type OperatorPolicyReadItem struct {
 	Id       string                 `json:"id,omitempty"`
@@ -34,6 +16,8 @@ type OperatorPolicyReadItem struct {
 	Allow3g  bool                   `json:"allow3g"`
 	Allow45g bool                   `json:"allow45g"`
 	Entries  *[]OperatorPolicyEntry `json:"entries"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type OperatorPolicyModifyItem struct {
@@ -57,19 +41,9 @@ type SteeringProfileEntryAction struct {
 	Active  bool   `json:"active"`
 }
 
-type OperatorPolicyEntryResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-}
+type OperatorPolicyEntryResponse = Result
 
-type OperatorPolicyEntryList struct {
-	Success    bool                  `json:"success"`
-	Messages   []string              `json:"messages"`
-	TotalItems int32                 `json:"totalItems"`
-	Limit      int32                 `json:"limit"`
-	Offset     int32                 `json:"offset"`
-	Data       []OperatorPolicyEntry `json:"data"`
-}
+type OperatorPolicyEntryList = Page[OperatorPolicyEntry]
 
 type OperatorPolicyEntryModifyItem struct {
 	OperatorId                 *int32  `json:"operatorId"`
@@ -2,29 +2,11 @@
 
 package models
 
-type RegionalPolicyList struct {
-	Success    bool                     `json:"success"`
-	Messages   []string                 `json:"messages"`
-	TotalItems int32                    `json:"totalItems"`
-	Limit      int32                    `json:"limit"`
-	Offset     int32                    `json:"offset"`
-	Data       []RegionalPolicyReadItem `json:"data"`
-}
+type RegionalPolicyList = Page[RegionalPolicyReadItem]
 
-type RegionalPolicySingle struct {
-	Success    bool                   `json:"success"`
-	Messages   []string               `json:"messages"`
-	TotalItems int32                  `json:"totalItems"`
-	Limit      int32                  `json:"limit"`
-	Offset     int32                  `json:"offset"`
-	Data       RegionalPolicyReadItem `json:"data"`
-}
+type RegionalPolicySingle = Single[RegionalPolicyReadItem]
 
-type RegionalPolicyResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type RegionalPolicyResponse = Result
 
 type RegionalPolicyReadItem struct {
 	Id      string                 `json:"id,omitempty"`
@@ -33,6 +15,8 @@ type RegionalPolicyReadItem struct {
 	Active  bool                   `json:"active"`
 	IsFixed bool                   `json:"isFixed"`
 	Entries *[]RegionalPolicyEntry `json:"entries"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type RegionalPolicyModifyItem struct {
@@ -55,19 +39,9 @@ type regionalGateway struct {
 	Name    string `json:"name"`
 }
 
-type RegionalPolicyEntryResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-}
+type RegionalPolicyEntryResponse = Result
 
-type RegionalPolicyEntryList struct {
-	Success    bool                  `json:"success"`
-	Messages   []string              `json:"messages"`
-	TotalItems int32                 `json:"totalItems"`
-	Limit      int32                 `json:"limit"`
-	Offset     int32                 `json:"offset"`
-	Data       []RegionalPolicyEntry `json:"data"`
-}
+type RegionalPolicyEntryList = Page[RegionalPolicyEntry]
 
 type RegionalPolicyEntryModifyItem struct {
 	RegionalGatewayId *string `json:"regionalGatewayId"`
@@ -2,29 +2,11 @@
 
 package models
 
-type RoutingPolicyList struct {
-	Success    bool                    `json:"success"`
-	Messages   []string                `json:"messages"`
-	TotalItems int32                   `json:"totalItems"`
-	Limit      int32                   `json:"limit"`
-	Offset     int32                   `json:"offset"`
-	Data       []RoutingPolicyReadItem `json:"data"`
-}
+type RoutingPolicyList = Page[RoutingPolicyReadItem]
 
-type RoutingPolicySingle struct {
-	Success    bool                  `json:"success"`
-	Messages   []string              `json:"messages"`
-	TotalItems int32                 `json:"totalItems"`
-	Limit      int32                 `json:"limit"`
-	Offset     int32                 `json:"offset"`
-	Data       RoutingPolicyReadItem `json:"data"`
-}
+type RoutingPolicySingle = Single[RoutingPolicyReadItem]
 
-type RoutingPolicyResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type RoutingPolicyResponse = Result
 
 type RoutingPolicyReadItem struct {
 	Id                              string         `json:"id,omitempty"`
@@ -38,6 +20,8 @@ type RoutingPolicyReadItem struct {
 	RoutingPolicyStatus             RoutingPolicy  `json:"routingPolicyStatus"`
 	RoutingPolicyRules              *[]Rule        `json:"routingPolicyRules"`
 	RoutingPolicyEdgeServices       *[]EdgeService `json:"routingPolicyEdgeServices"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type RoutingPolicyModifyItem struct {
@@ -2,29 +2,11 @@
 
 package models
 
-type RoutingTargetList struct {
-	Success    bool                    `json:"success"`
-	Messages   []string                `json:"messages"`
-	TotalItems int32                   `json:"totalItems"`
-	Limit      int32                   `json:"limit"`
-	Offset     int32                   `json:"offset"`
-	Data       []RoutingTargetReadItem `json:"data"`
-}
+type RoutingTargetList = Page[RoutingTargetReadItem]
 
-type RoutingTargetSingle struct {
-	Success    bool                  `json:"success"`
-	Messages   []string              `json:"messages"`
-	TotalItems int32                 `json:"totalItems"`
-	Limit      int32                 `json:"limit"`
-	Offset     int32                 `json:"offset"`
-	Data       RoutingTargetReadItem `json:"data"`
-}
+type RoutingTargetSingle = Single[RoutingTargetReadItem]
 
-type RoutingTargetResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type RoutingTargetResponse = Result
 
 type RoutingTargetReadItem struct {
 	Id                           string                    `json:"id,omitempty"`
@@ -41,6 +23,8 @@ type RoutingTargetReadItem struct {
 	RoutingRedundancyZoneName    string                    `json:"routingRedundancyZoneName"`
 	RegionalGatewayMoniker       string                    `json:"regionalGatewayMoniker"`
 	RegionalGatewayName          string                    `json:"regionalGatewayName"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type RoutingTargetTypeInstance struct {
@@ -2,29 +2,11 @@
 
 package models
 
-type VSliceList struct {
-	Success    bool             `json:"success"`
-	Messages   []string         `json:"messages"`
-	TotalItems int32            `json:"totalItems"`
-	Limit      int32            `json:"limit"`
-	Offset     int32            `json:"offset"`
-	Data       []VSliceReadItem `json:"data"`
-}
+type VSliceList = Page[VSliceReadItem]
 
-type VSliceSingle struct {
-	Success    bool           `json:"success"`
-	Messages   []string       `json:"messages"`
-	TotalItems int32          `json:"totalItems"`
-	Limit      int32          `json:"limit"`
-	Offset     int32          `json:"offset"`
-	Data       VSliceReadItem `json:"data"`
-}
+type VSliceSingle = Single[VSliceReadItem]
 
-type VSliceResponse struct {
-	Success  bool     `json:"success"`
-	Messages []string `json:"messages"`
-	Data     string   `json:"data"`
-}
+type VSliceResponse = Result
 
 type VSlice struct {
 	Id      string `json:"id,omitempty"`
@@ -43,6 +25,8 @@ type VSliceReadItem struct {
 	IpAddressFamily    IpAddressFamily `json:"ipAddressFamily"`
 	EndpointGroupCount int32           `json:"endpointGroupCount"`
 	EndpointCount      int32           `json:"endpointCount"`
+	// Version is the ETag the object was read with. It is not part of the JSON body.
+	Version string `json:"-"`
 }
 
 type VSliceModifyItem struct {
@@ -3,210 +3,112 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
+	"iter"
 	"net/http"
-	"strings"
+	"net/url"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetOperatorPolicies - Returns list of OperatorPolicies
-func (c *Client) GetOperatorPolicies(pagingState models.PagingState) ([]models.OperatorPolicyReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	OperatorPolicyItems := []models.OperatorPolicyReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/OperatorPolicies?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return OperatorPolicyItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return OperatorPolicyItems, err
-	}
+// operatorPolicies - Returns the collection backing the OperatorPolicy methods
+func (c *Client) operatorPolicies() Collection[models.OperatorPolicyReadItem, models.OperatorPolicyModifyItem] {
+	return NewCollection[models.OperatorPolicyReadItem, models.OperatorPolicyModifyItem](c, "OperatorPolicies")
+}
 
-	apiResponse := models.OperatorPolicyList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return OperatorPolicyItems, err
-	}
+// GetOperatorPolicies - Returns list of OperatorPolicies
+func (c *Client) GetOperatorPolicies(ctx context.Context, pagingState models.PagingState) ([]models.OperatorPolicyReadItem, error) {
+	return c.operatorPolicies().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return OperatorPolicyItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetOperatorPoliciesPage - Returns a single page of OperatorPolicies along with the paging totals
+func (c *Client) GetOperatorPoliciesPage(ctx context.Context, pagingState models.PagingState) (models.OperatorPolicyList, error) {
+	return c.operatorPolicies().ListPage(ctx, pagingState)
+}
 
-	OperatorPolicyItems = append(OperatorPolicyItems, apiResponse.Data...)
+// AllOperatorPolicies - Iterates over all OperatorPolicies matching pagingState, fetching pages as needed
+func (c *Client) AllOperatorPolicies(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.OperatorPolicyReadItem, error] {
+	return c.operatorPolicies().All(ctx, pagingState)
+}
 
-	return OperatorPolicyItems, nil
+// GetAllOperatorPolicies - Returns all OperatorPolicies matching pagingState across all pages
+func (c *Client) GetAllOperatorPolicies(ctx context.Context, pagingState models.PagingState) ([]models.OperatorPolicyReadItem, error) {
+	return c.operatorPolicies().ListAll(ctx, pagingState)
 }
 
 // GetOperatorPolicy - Returns a specific OperatorPolicy
-func (c *Client) GetOperatorPolicy(OperatorPolicyId string) (models.OperatorPolicyReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/OperatorPolicies/%s", c.HostURL, OperatorPolicyId), nil)
-	if err != nil {
-		return models.OperatorPolicyReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.OperatorPolicyReadItem{}, err
-	}
-
-	apiResponse := models.OperatorPolicySingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.OperatorPolicyReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.OperatorPolicyReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return apiResponse.Data, nil
+func (c *Client) GetOperatorPolicy(ctx context.Context, OperatorPolicyId string) (models.OperatorPolicyReadItem, error) {
+	item, version, err := c.operatorPolicies().GetWithVersion(ctx, OperatorPolicyId)
+	item.Version = version
+	return item, err
 }
 
 // GetOperatorPolicyEntries - Returns a specific OperatorPolicy entries
-func (c *Client) GetOperatorPolicyEntries(OperatorPolicyId string) ([]models.OperatorPolicyEntry, error) {
-	OperatorPolicyEntries := []models.OperatorPolicyEntry{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/OperatorPolicies/%s/entries", c.HostURL, OperatorPolicyId), nil)
-	if err != nil {
-		return OperatorPolicyEntries, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return OperatorPolicyEntries, err
-	}
-
+func (c *Client) GetOperatorPolicyEntries(ctx context.Context, OperatorPolicyId string) ([]models.OperatorPolicyEntry, error) {
 	apiResponse := models.OperatorPolicyEntryList{}
-	err = json.Unmarshal(body, &apiResponse)
+	err := c.send(ctx, http.MethodGet, c.operatorPolicies().ItemPath(OperatorPolicyId, "entries"), nil, &apiResponse)
 	if err != nil {
-		return OperatorPolicyEntries, err
-	}
-
-	if !apiResponse.Success {
-		return OperatorPolicyEntries, errors.New(strings.Join(apiResponse.Messages, " "))
+		return []models.OperatorPolicyEntry{}, err
 	}
 
-	OperatorPolicyEntries = append(OperatorPolicyEntries, apiResponse.Data...)
-
-	return OperatorPolicyEntries, nil
+	return append([]models.OperatorPolicyEntry{}, apiResponse.Data...), nil
 }
 
-// CreateOperatorPolicy - Create a new Operator Policy
-func (c *Client) CreateOperatorPolicy(OperatorPolicy models.OperatorPolicyModifyItem) (*models.OperatorPolicyResponse, error) {
-	rb, err := json.Marshal(OperatorPolicy)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/OperatorPolicies", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// CreateOperatorPolicy - Create a new Operator Policy, adopting an existing policy with the same moniker and configuration
+func (c *Client) CreateOperatorPolicy(ctx context.Context, OperatorPolicy models.OperatorPolicyModifyItem) (*models.OperatorPolicyResponse, error) {
+	return c.operatorPolicies().CreateOrAdopt(ctx, OperatorPolicy, OperatorPolicy.Moniker, operatorPolicyMatches, func(existing models.OperatorPolicyReadItem) string {
+		return existing.Id
+	})
+}
 
-	apiResponse := models.OperatorPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
+// AddOperatorPolicyEntries - add new Operator Policy entries
+func (c *Client) AddOperatorPolicyEntries(ctx context.Context, OperatorPolicyEntries []models.OperatorPolicyEntryModifyItem, OperatorPolicyId string) (*models.OperatorPolicyEntryResponse, error) {
+	apiResponse := models.OperatorPolicyEntryResponse{}
+	err := c.send(ctx, http.MethodPost, c.operatorPolicies().ItemPath(OperatorPolicyId, "entries"), OperatorPolicyEntries, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
 	return &apiResponse, nil
 }
 
-// AddOperatorPolicyEntries - add new Operator Policy entries
-func (c *Client) AddOperatorPolicyEntries(OperatorPolicyEntries []models.OperatorPolicyEntryModifyItem, OperatorPolicyId string) (*models.OperatorPolicyEntryResponse, error) {
-	rb, err := json.Marshal(OperatorPolicyEntries)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/OperatorPolicies/%s/entries", c.HostURL, OperatorPolicyId), strings.NewReader(string(rb)))
+// UpdateOperatorPolicyEntry - Replace a single Operator Policy entry
+func (c *Client) UpdateOperatorPolicyEntry(ctx context.Context, OperatorPolicyId string, EntryId string, OperatorPolicyEntry models.OperatorPolicyEntryModifyItem) (*models.OperatorPolicyEntryResponse, error) {
+	apiResponse := models.OperatorPolicyEntryResponse{}
+	err := c.send(ctx, http.MethodPut, c.operatorPolicies().ItemPath(OperatorPolicyId, "entries/"+url.PathEscape(EntryId)), OperatorPolicyEntry, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+	return &apiResponse, nil
+}
 
+// DeleteOperatorPolicyEntry - Delete a single Operator Policy entry
+func (c *Client) DeleteOperatorPolicyEntry(ctx context.Context, OperatorPolicyId string, EntryId string) (*models.OperatorPolicyEntryResponse, error) {
 	apiResponse := models.OperatorPolicyEntryResponse{}
-	err = json.Unmarshal(body, &apiResponse)
+	err := c.send(ctx, http.MethodDelete, c.operatorPolicies().ItemPath(OperatorPolicyId, "entries/"+url.PathEscape(EntryId)), nil, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
 	return &apiResponse, nil
 }
 
 // UpdateOperatorPolicy - Update a new Operator Policy
-func (c *Client) UpdateOperatorPolicy(OperatorPolicyId string, OperatorPolicy models.OperatorPolicyModifyItem) (*models.OperatorPolicyResponse, error) {
-	rb, err := json.Marshal(OperatorPolicy)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/OperatorPolicies/%s", c.HostURL, OperatorPolicyId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.OperatorPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateOperatorPolicy(ctx context.Context, OperatorPolicyId string, OperatorPolicy models.OperatorPolicyModifyItem, version string) (*models.OperatorPolicyResponse, error) {
+	return c.operatorPolicies().Update(ctx, OperatorPolicyId, OperatorPolicy, version)
 }
 
 // DeleteOperatorPolicy - Delete a Operator Policy
-func (c *Client) DeleteOperatorPolicy(OperatorPolicyId string) (*models.OperatorPolicyResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/OperatorPolicies/%s", c.HostURL, OperatorPolicyId), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.OperatorPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+func (c *Client) DeleteOperatorPolicy(ctx context.Context, OperatorPolicyId string) (*models.OperatorPolicyResponse, error) {
+	return c.operatorPolicies().Delete(ctx, OperatorPolicyId)
+}
 
-	return &apiResponse, nil
+// operatorPolicyMatches reports whether an existing operator policy has the configuration requested
+// by want. Entries are added after the policy is created, so a policy that already has some is not adopted.
+func operatorPolicyMatches(existing models.OperatorPolicyReadItem, want models.OperatorPolicyModifyItem) bool {
+	return existing.Name == want.Name &&
+		(existing.Entries == nil || len(*existing.Entries) == 0)
 }
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"context"
+	"iter"
+
+	"stacuity.com/go_client/models"
+)
+
+// DefaultPageSize - Page size used when walking every page of a list endpoint without an explicit limit
+const DefaultPageSize int32 = 100
+
+// pageFetcher returns the items of one page together with the TotalItems reported by the API.
+type pageFetcher[T any] func(ctx context.Context, pagingState models.PagingState) ([]T, int32, error)
+
+// allPages walks a list endpoint from pagingState.Offset until TotalItems is reached,
+// yielding each item in order. The limit of pagingState is used as the page size.
+// Iteration stops at the first error, which is yielded with the zero value of T.
+func allPages[T any](ctx context.Context, pagingState models.PagingState, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		page := pagingState
+		if page.Limit <= 0 {
+			page.Limit = DefaultPageSize
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			items, totalItems, err := fetch(ctx, page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			// An empty page guards against looping forever if TotalItems is stale or missing.
+			page.Offset += int32(len(items))
+			if len(items) == 0 || page.Offset >= totalItems {
+				return
+			}
+		}
+	}
+}
+
+// collectPages drains an iterator returned by allPages into a slice.
+func collectPages[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	items := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
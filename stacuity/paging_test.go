@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"stacuity.com/go_client/models"
+)
+
+// pagedFetcher serves items in pages, failing with failErr at the offset failAt when set, and
+// records the paging state of every fetch.
+type pagedFetcher struct {
+	items   []int
+	total   int32
+	failAt  int32
+	failErr error
+	calls   []models.PagingState
+}
+
+func (f *pagedFetcher) fetch(_ context.Context, page models.PagingState) ([]int, int32, error) {
+	f.calls = append(f.calls, page)
+	if f.failErr != nil && page.Offset >= f.failAt {
+		return nil, 0, f.failErr
+	}
+
+	from := min(int(page.Offset), len(f.items))
+	to := min(from+int(page.Limit), len(f.items))
+	return f.items[from:to], f.total, nil
+}
+
+func newPagedFetcher(count int) *pagedFetcher {
+	f := &pagedFetcher{total: int32(count)}
+	for i := range count {
+		f.items = append(f.items, i)
+	}
+	return f
+}
+
+func TestAllPages(t *testing.T) {
+	tests := map[string]struct {
+		fetcher    *pagedFetcher
+		paging     models.PagingState
+		wantItems  []int
+		wantErr    bool
+		wantOffset []int32
+	}{
+		"single page": {
+			fetcher:    newPagedFetcher(3),
+			paging:     models.PagingState{Limit: 5},
+			wantItems:  []int{0, 1, 2},
+			wantOffset: []int32{0},
+		},
+		"multiple pages": {
+			fetcher:    newPagedFetcher(7),
+			paging:     models.PagingState{Limit: 3},
+			wantItems:  []int{0, 1, 2, 3, 4, 5, 6},
+			wantOffset: []int32{0, 3, 6},
+		},
+		"exact multiple of the page size": {
+			fetcher:    newPagedFetcher(6),
+			paging:     models.PagingState{Limit: 3},
+			wantItems:  []int{0, 1, 2, 3, 4, 5},
+			wantOffset: []int32{0, 3},
+		},
+		"starting offset": {
+			fetcher:    newPagedFetcher(7),
+			paging:     models.PagingState{Offset: 4, Limit: 2},
+			wantItems:  []int{4, 5, 6},
+			wantOffset: []int32{4, 6},
+		},
+		"default page size": {
+			fetcher:    newPagedFetcher(int(DefaultPageSize) + 1),
+			wantItems:  newPagedFetcher(int(DefaultPageSize) + 1).items,
+			wantOffset: []int32{0, DefaultPageSize},
+		},
+		"no items": {
+			fetcher:    newPagedFetcher(0),
+			paging:     models.PagingState{Limit: 3},
+			wantItems:  []int{},
+			wantOffset: []int32{0},
+		},
+		"total larger than the items served": {
+			fetcher:    &pagedFetcher{items: []int{0, 1, 2, 3}, total: 100},
+			paging:     models.PagingState{Limit: 3},
+			wantItems:  []int{0, 1, 2, 3},
+			wantOffset: []int32{0, 3, 4},
+		},
+		"error on a later page": {
+			fetcher:    &pagedFetcher{items: []int{0, 1, 2, 3, 4, 5, 6}, total: 7, failAt: 3, failErr: errors.New("page failed")},
+			paging:     models.PagingState{Limit: 3},
+			wantItems:  []int{0, 1, 2},
+			wantErr:    true,
+			wantOffset: []int32{0, 3},
+		},
+		"error on the first page": {
+			fetcher:    &pagedFetcher{total: 7, failErr: errors.New("page failed")},
+			paging:     models.PagingState{Limit: 3},
+			wantItems:  []int{},
+			wantErr:    true,
+			wantOffset: []int32{0},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			items, err := collectPages(allPages(context.Background(), test.paging, test.fetcher.fetch))
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+			if !slices.Equal(items, test.wantItems) {
+				t.Errorf("got items %v, want %v", items, test.wantItems)
+			}
+
+			var offsets []int32
+			for _, call := range test.fetcher.calls {
+				offsets = append(offsets, call.Offset)
+			}
+			if !slices.Equal(offsets, test.wantOffset) {
+				t.Errorf("fetched offsets %v, want %v", offsets, test.wantOffset)
+			}
+		})
+	}
+}
+
+func TestAllPagesStopsEarly(t *testing.T) {
+	fetcher := newPagedFetcher(10)
+
+	var items []int
+	for item, err := range allPages(context.Background(), models.PagingState{Limit: 3}, fetcher.fetch) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, item)
+		if item == 4 {
+			break
+		}
+	}
+
+	if !slices.Equal(items, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("got items %v", items)
+	}
+	if len(fetcher.calls) != 2 {
+		t.Errorf("fetched %d pages after stopping early, want 2", len(fetcher.calls))
+	}
+}
+
+func TestAllPagesCancelled(t *testing.T) {
+	fetcher := newPagedFetcher(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var items []int
+	var gotErr error
+	for item, err := range allPages(ctx, models.PagingState{Limit: 3}, fetcher.fetch) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		items = append(items, item)
+		if item == 2 {
+			cancel()
+		}
+	}
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("got error %v, want %v", gotErr, context.Canceled)
+	}
+	if len(items) != 3 || len(fetcher.calls) != 1 {
+		t.Errorf("got items %v from %d pages after cancelling, want 3 items from 1 page", items, len(fetcher.calls))
+	}
+}
@@ -3,213 +3,116 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
+	"iter"
 	"net/http"
-	"strings"
+	"net/url"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetRegionalPolicies - Returns list of RegionalPolicies
-func (c *Client) GetRegionalPolicies(pagingState models.PagingState) ([]models.RegionalPolicyReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	RegionalPolicyItems := []models.RegionalPolicyReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/RegionalPolicies?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return RegionalPolicyItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return RegionalPolicyItems, err
-	}
+// regionalPolicies - Returns the collection backing the RegionalPolicy methods
+func (c *Client) regionalPolicies() Collection[models.RegionalPolicyReadItem, models.RegionalPolicyModifyItem] {
+	return NewCollection[models.RegionalPolicyReadItem, models.RegionalPolicyModifyItem](c, "RegionalPolicies")
+}
 
-	apiResponse := models.RegionalPolicyList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return RegionalPolicyItems, err
-	}
+// GetRegionalPolicies - Returns list of RegionalPolicies
+func (c *Client) GetRegionalPolicies(ctx context.Context, pagingState models.PagingState) ([]models.RegionalPolicyReadItem, error) {
+	return c.regionalPolicies().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return RegionalPolicyItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetRegionalPoliciesPage - Returns a single page of RegionalPolicies along with the paging totals
+func (c *Client) GetRegionalPoliciesPage(ctx context.Context, pagingState models.PagingState) (models.RegionalPolicyList, error) {
+	return c.regionalPolicies().ListPage(ctx, pagingState)
+}
 
-	RegionalPolicyItems = append(RegionalPolicyItems, apiResponse.Data...)
+// AllRegionalPolicies - Iterates over all RegionalPolicies matching pagingState, fetching pages as needed
+func (c *Client) AllRegionalPolicies(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.RegionalPolicyReadItem, error] {
+	return c.regionalPolicies().All(ctx, pagingState)
+}
 
-	return RegionalPolicyItems, nil
+// GetAllRegionalPolicies - Returns all RegionalPolicies matching pagingState across all pages
+func (c *Client) GetAllRegionalPolicies(ctx context.Context, pagingState models.PagingState) ([]models.RegionalPolicyReadItem, error) {
+	return c.regionalPolicies().ListAll(ctx, pagingState)
 }
 
 // GetRegionalPolicy - Returns a specific RegionalPolicy
-func (c *Client) GetRegionalPolicy(RegionalPolicyId string) (models.RegionalPolicyReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/RegionalPolicies/%s", c.HostURL, RegionalPolicyId), nil)
-	if err != nil {
-		return models.RegionalPolicyReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.RegionalPolicyReadItem{}, err
-	}
-
-	apiResponse := models.RegionalPolicySingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.RegionalPolicyReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.RegionalPolicyReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return apiResponse.Data, nil
+func (c *Client) GetRegionalPolicy(ctx context.Context, RegionalPolicyId string) (models.RegionalPolicyReadItem, error) {
+	item, version, err := c.regionalPolicies().GetWithVersion(ctx, RegionalPolicyId)
+	item.Version = version
+	return item, err
 }
 
 // GetRegionalPolicyEntries - Returns a specific RegionalPolicy entries
-func (c *Client) GetRegionalPolicyEntries(RegionalPolicyId string) ([]models.RegionalPolicyEntry, error) {
-	RegionalPolicyEntries := []models.RegionalPolicyEntry{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/RegionalPolicies/%s/entries", c.HostURL, RegionalPolicyId), nil)
-	if err != nil {
-		return RegionalPolicyEntries, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return RegionalPolicyEntries, err
-	}
-
+func (c *Client) GetRegionalPolicyEntries(ctx context.Context, RegionalPolicyId string) ([]models.RegionalPolicyEntry, error) {
 	apiResponse := models.RegionalPolicyEntryList{}
-	err = json.Unmarshal(body, &apiResponse)
+	err := c.send(ctx, http.MethodGet, c.regionalPolicies().ItemPath(RegionalPolicyId, "entries"), nil, &apiResponse)
 	if err != nil {
-		return RegionalPolicyEntries, err
+		return []models.RegionalPolicyEntry{}, err
 	}
 
-	if !apiResponse.Success {
-		return RegionalPolicyEntries, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	RegionalPolicyEntries = append(RegionalPolicyEntries, apiResponse.Data...)
-
-	return RegionalPolicyEntries, nil
+	return append([]models.RegionalPolicyEntry{}, apiResponse.Data...), nil
 }
 
-// CreateRegionalPolicy - Create a new Regional Policy
-func (c *Client) CreateRegionalPolicy(RegionalPolicy models.RegionalPolicyModifyItem) (*models.RegionalPolicyResponse, error) {
-	rb, err := json.Marshal(RegionalPolicy)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/RegionalPolicies", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.RegionalPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// CreateRegionalPolicy - Create a new Regional Policy, adopting an existing policy with the same moniker and configuration
+func (c *Client) CreateRegionalPolicy(ctx context.Context, RegionalPolicy models.RegionalPolicyModifyItem) (*models.RegionalPolicyResponse, error) {
+	return c.regionalPolicies().CreateOrAdopt(ctx, RegionalPolicy, RegionalPolicy.Moniker, regionalPolicyMatches, func(existing models.RegionalPolicyReadItem) string {
+		return existing.Id
+	})
 }
 
 // AddRegionalPolicyEntries - add new Regional Policy entries
-func (c *Client) AddRegionalPolicyEntries(RegionalPolicyEntries []models.RegionalPolicyEntryModifyItem, RegionalPolicyId string) (*models.RegionalPolicyEntryResponse, error) {
+func (c *Client) AddRegionalPolicyEntries(ctx context.Context, RegionalPolicyEntries []models.RegionalPolicyEntryModifyItem, RegionalPolicyId string) (*models.RegionalPolicyEntryResponse, error) {
 	apiResponse := models.RegionalPolicyEntryResponse{}
 
+	// The API only accepts a single entry per call.
 	for _, elem := range RegionalPolicyEntries {
-		rb, err := json.Marshal(elem)
+		err := c.send(ctx, http.MethodPost, c.regionalPolicies().ItemPath(RegionalPolicyId, "entries"), elem, &apiResponse)
 		if err != nil {
 			return nil, err
 		}
-
-		req, err := http.NewRequest("POST", fmt.Sprintf("%s/RegionalPolicies/%s/entries", c.HostURL, RegionalPolicyId), strings.NewReader(string(rb)))
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := c.doRequest(req)
-		if err != nil {
-			return nil, err
-		}
-
-		err = json.Unmarshal(body, &apiResponse)
-		if err != nil {
-			return nil, err
-		}
-
-		if !apiResponse.Success {
-			return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-		}
 	}
 
 	return &apiResponse, nil
 }
 
-// UpdateRegionalPolicy - Update a new Regional Policy
-func (c *Client) UpdateRegionalPolicy(RegionalPolicyId string, RegionalPolicy models.RegionalPolicyModifyItem) (*models.RegionalPolicyResponse, error) {
-	rb, err := json.Marshal(RegionalPolicy)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/RegionalPolicies/%s", c.HostURL, RegionalPolicyId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.RegionalPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
+// UpdateRegionalPolicyEntry - Replace a single Regional Policy entry
+func (c *Client) UpdateRegionalPolicyEntry(ctx context.Context, RegionalPolicyId string, EntryId string, RegionalPolicyEntry models.RegionalPolicyEntryModifyItem) (*models.RegionalPolicyEntryResponse, error) {
+	apiResponse := models.RegionalPolicyEntryResponse{}
+	err := c.send(ctx, http.MethodPut, c.regionalPolicies().ItemPath(RegionalPolicyId, "entries/"+url.PathEscape(EntryId)), RegionalPolicyEntry, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
 	return &apiResponse, nil
 }
 
-// DeleteRegionalPolicy - Delete a Regional Policy
-func (c *Client) DeleteRegionalPolicy(RegionalPolicyId string) (*models.RegionalPolicyResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/RegionalPolicies/%s", c.HostURL, RegionalPolicyId), nil)
+// DeleteRegionalPolicyEntry - Delete a single Regional Policy entry
+func (c *Client) DeleteRegionalPolicyEntry(ctx context.Context, RegionalPolicyId string, EntryId string) (*models.RegionalPolicyEntryResponse, error) {
+	apiResponse := models.RegionalPolicyEntryResponse{}
+	err := c.send(ctx, http.MethodDelete, c.regionalPolicies().ItemPath(RegionalPolicyId, "entries/"+url.PathEscape(EntryId)), nil, &apiResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
+	return &apiResponse, nil
+}
 
-	apiResponse := models.RegionalPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
+// UpdateRegionalPolicy - Update a new Regional Policy
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateRegionalPolicy(ctx context.Context, RegionalPolicyId string, RegionalPolicy models.RegionalPolicyModifyItem, version string) (*models.RegionalPolicyResponse, error) {
+	return c.regionalPolicies().Update(ctx, RegionalPolicyId, RegionalPolicy, version)
+}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// DeleteRegionalPolicy - Delete a Regional Policy
+func (c *Client) DeleteRegionalPolicy(ctx context.Context, RegionalPolicyId string) (*models.RegionalPolicyResponse, error) {
+	return c.regionalPolicies().Delete(ctx, RegionalPolicyId)
+}
 
-	return &apiResponse, nil
+// regionalPolicyMatches reports whether an existing regional policy has the configuration requested
+// by want. Entries are added after the policy is created, so a policy that already has some is not adopted.
+func regionalPolicyMatches(existing models.RegionalPolicyReadItem, want models.RegionalPolicyModifyItem) bool {
+	return existing.Name == want.Name &&
+		(existing.Entries == nil || len(*existing.Entries) == 0)
 }
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxAttempts - Default number of attempts made for a retryable request
+const DefaultMaxAttempts int = 4
+
+// RetryPolicy - Controls how doRequest retries failed calls to the Stacuity API
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Values below 1 disable retries.
+	MaxAttempts int
+	// MinBackoff is the base delay used for the first retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including delays requested via Retry-After.
+	MaxBackoff time.Duration
+	// RetryNonIdempotent allows POST (and other non-idempotent) requests to be retried.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy - Returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// retryable reports whether a request may be attempted again after the given result.
+func (p RetryPolicy) retryable(req *http.Request, res *http.Response, err error, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	// Never retry once the caller has given up.
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	// A create carrying an idempotency key cannot be applied twice, so it may be retried.
+	if !p.RetryNonIdempotent && !isIdempotent(req.Method) && req.Header.Get(IdempotencyKeyHeader) == "" {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		// A replayed request without a recording will not gain one by trying again.
+		return !errors.Is(err, ErrCassetteMiss)
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented)
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After header on
+// the previous response takes precedence over the computed exponential delay.
+func (p RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+				return p.MaxBackoff
+			}
+			return wait
+		}
+	}
+
+	base := float64(p.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && base > float64(p.MaxBackoff) {
+		base = float64(p.MaxBackoff)
+	}
+
+	// Full jitter keeps parallel operations from retrying in lock step.
+	return time.Duration(rand.Int63n(int64(base) + 1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter understands both forms allowed by RFC 9110: delay-seconds and HTTP-date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		wait := time.Until(when)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
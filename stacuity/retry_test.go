@@ -0,0 +1,261 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy retries quickly so tests do not wait on real backoff delays.
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}
+}
+
+// newRetryServer serves failures with the given status until failures have been sent, then
+// succeeds. The returned counter holds the number of requests received.
+func newRetryServer(t *testing.T, status int, retryAfter string, failures int32) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	attempts := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := attempts.Add(1); n <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"data":"done"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	token := "token"
+	client, err := NewClient(&server.URL, &token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Retry = testRetryPolicy()
+
+	return client, attempts
+}
+
+func TestDoRequestRetries(t *testing.T) {
+	tests := map[string]struct {
+		status       int
+		retryAfter   string
+		failures     int32
+		method       string
+		key          string
+		wantAttempts int32
+		wantErr      func(error) bool
+	}{
+		"429 with Retry-After seconds": {
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "0",
+			failures:     2,
+			method:       http.MethodGet,
+			wantAttempts: 3,
+		},
+		"429 with Retry-After above MaxBackoff": {
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "120",
+			failures:     1,
+			method:       http.MethodGet,
+			wantAttempts: 2,
+		},
+		"503 with Retry-After date": {
+			status:       http.StatusServiceUnavailable,
+			retryAfter:   time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat),
+			failures:     3,
+			method:       http.MethodPut,
+			wantAttempts: 4,
+		},
+		"503 until attempts run out": {
+			status:       http.StatusServiceUnavailable,
+			failures:     10,
+			method:       http.MethodDelete,
+			wantAttempts: int32(DefaultMaxAttempts),
+			wantErr:      IsServerError,
+		},
+		"429 until attempts run out": {
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "0",
+			failures:     10,
+			method:       http.MethodGet,
+			wantAttempts: int32(DefaultMaxAttempts),
+			wantErr:      IsRateLimited,
+		},
+		"501 is not retried": {
+			status:       http.StatusNotImplemented,
+			failures:     1,
+			method:       http.MethodGet,
+			wantAttempts: 1,
+			wantErr:      IsServerError,
+		},
+		"404 is not retried": {
+			status:       http.StatusNotFound,
+			failures:     1,
+			method:       http.MethodGet,
+			wantAttempts: 1,
+			wantErr:      IsNotFound,
+		},
+		"POST without idempotency key is not retried": {
+			status:       http.StatusServiceUnavailable,
+			failures:     1,
+			method:       http.MethodPost,
+			wantAttempts: 1,
+			wantErr:      IsServerError,
+		},
+		"POST without idempotency key is not retried on 429": {
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "0",
+			failures:     1,
+			method:       http.MethodPost,
+			wantAttempts: 1,
+			wantErr:      IsRateLimited,
+		},
+		"POST with idempotency key is retried": {
+			status:       http.StatusServiceUnavailable,
+			failures:     2,
+			method:       http.MethodPost,
+			key:          "key-1",
+			wantAttempts: 3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, attempts := newRetryServer(t, test.status, test.retryAfter, test.failures)
+
+			var header http.Header
+			if test.key != "" {
+				header = http.Header{IdempotencyKeyHeader: []string{test.key}}
+			}
+
+			var out any
+			start := time.Now()
+			_, err := client.sendWithHeader(context.Background(), test.method, "items", header, map[string]string{"name": "a"}, &out)
+
+			if test.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if test.wantErr != nil && !test.wantErr(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := attempts.Load(); got != test.wantAttempts {
+				t.Errorf("got %d attempts, want %d", got, test.wantAttempts)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("retries took %s, Retry-After was not capped by MaxBackoff", elapsed)
+			}
+		})
+	}
+}
+
+func TestDoRequestRetryResendsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	token := "token"
+	client, _ := NewClient(&server.URL, &token)
+	client.Retry = testRetryPolicy()
+
+	var out any
+	if err := client.send(context.Background(), http.MethodPut, "items/a", map[string]string{"name": "a"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("retried request did not resend the body: %q", bodies)
+	}
+}
+
+func TestDoRequestRetryStopsWhenCancelled(t *testing.T) {
+	client, attempts := newRetryServer(t, http.StatusServiceUnavailable, "", 10)
+	client.Retry.MinBackoff = time.Hour
+	client.Retry.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out any
+	err := client.send(ctx, http.MethodGet, "items", nil, &out)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	retryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	if got := policy.backoff(1, retryAfter("0")); got != 0 {
+		t.Errorf("Retry-After 0: got %s, want 0", got)
+	}
+	if got := policy.backoff(1, retryAfter("120")); got != time.Second {
+		t.Errorf("Retry-After above MaxBackoff: got %s, want %s", got, time.Second)
+	}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		limit := min(policy.MinBackoff<<(attempt-1), policy.MaxBackoff)
+		if got := policy.backoff(attempt, retryAfter("soon")); got < 0 || got > limit {
+			t.Errorf("attempt %d: got %s, want at most %s", attempt, got, limit)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		"empty":        {value: "", wantOK: false},
+		"seconds":      {value: "3", want: 3 * time.Second, wantOK: true},
+		"zero":         {value: "0", want: 0, wantOK: true},
+		"negative":     {value: "-1", wantOK: false},
+		"garbage":      {value: "later", wantOK: false},
+		"past date":    {value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0, wantOK: true},
+		"large number": {value: strconv.Itoa(86400), want: 24 * time.Hour, wantOK: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := parseRetryAfter(test.value)
+			if ok != test.wantOK || got != test.want {
+				t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", test.value, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got, ok := parseRetryAfter(future); !ok || got <= 58*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(%q) = %s, %t, want about an hour", future, got, ok)
+	}
+}
@@ -3,152 +3,133 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
+	"iter"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetRoutingPolicies - Returns list of RoutingPolicies
-func (c *Client) GetRoutingPolicies(pagingState models.PagingState) ([]models.RoutingPolicyReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	RoutingPolicyItems := []models.RoutingPolicyReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/RoutingPolicies?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return RoutingPolicyItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return RoutingPolicyItems, err
-	}
+// routingPolicies - Returns the collection backing the RoutingPolicy methods
+func (c *Client) routingPolicies() Collection[models.RoutingPolicyReadItem, models.RoutingPolicyModifyItem] {
+	return NewCollection[models.RoutingPolicyReadItem, models.RoutingPolicyModifyItem](c, "RoutingPolicies")
+}
 
-	apiResponse := models.RoutingPolicyList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return RoutingPolicyItems, err
-	}
+// GetRoutingPolicies - Returns list of RoutingPolicies
+func (c *Client) GetRoutingPolicies(ctx context.Context, pagingState models.PagingState) ([]models.RoutingPolicyReadItem, error) {
+	return c.routingPolicies().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return RoutingPolicyItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetRoutingPoliciesPage - Returns a single page of RoutingPolicies along with the paging totals
+func (c *Client) GetRoutingPoliciesPage(ctx context.Context, pagingState models.PagingState) (models.RoutingPolicyList, error) {
+	return c.routingPolicies().ListPage(ctx, pagingState)
+}
 
-	RoutingPolicyItems = append(RoutingPolicyItems, apiResponse.Data...)
+// AllRoutingPolicies - Iterates over all RoutingPolicies matching pagingState, fetching pages as needed
+func (c *Client) AllRoutingPolicies(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.RoutingPolicyReadItem, error] {
+	return c.routingPolicies().All(ctx, pagingState)
+}
 
-	return RoutingPolicyItems, nil
+// GetAllRoutingPolicies - Returns all RoutingPolicies matching pagingState across all pages
+func (c *Client) GetAllRoutingPolicies(ctx context.Context, pagingState models.PagingState) ([]models.RoutingPolicyReadItem, error) {
+	return c.routingPolicies().ListAll(ctx, pagingState)
 }
 
 // GetRoutingPolicy - Returns a specific RoutingPolicy
-func (c *Client) GetRoutingPolicy(RoutingPolicyId string) (models.RoutingPolicyReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/RoutingPolicies/%s", c.HostURL, RoutingPolicyId), nil)
-	if err != nil {
-		return models.RoutingPolicyReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.RoutingPolicyReadItem{}, err
-	}
-
-	apiResponse := models.RoutingPolicySingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.RoutingPolicyReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.RoutingPolicyReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+func (c *Client) GetRoutingPolicy(ctx context.Context, RoutingPolicyId string) (models.RoutingPolicyReadItem, error) {
+	item, version, err := c.routingPolicies().GetWithVersion(ctx, RoutingPolicyId)
+	item.Version = version
+	return item, err
+}
 
-	return apiResponse.Data, nil
+// CreateRoutingPolicy - Create a new Routing Policy, adopting an existing policy with the same moniker and configuration
+func (c *Client) CreateRoutingPolicy(ctx context.Context, RoutingPolicy models.RoutingPolicyModifyItem) (*models.RoutingPolicyResponse, error) {
+	return c.routingPolicies().CreateOrAdopt(ctx, RoutingPolicy, RoutingPolicy.Moniker, routingPolicyMatches, func(existing models.RoutingPolicyReadItem) string {
+		return existing.Id
+	})
 }
 
-// CreateRoutingPolicy - Create a new Routing Policy
-func (c *Client) CreateRoutingPolicy(RoutingPolicy models.RoutingPolicyModifyItem) (*models.RoutingPolicyResponse, error) {
-	rb, err := json.Marshal(RoutingPolicy)
-	if err != nil {
-		return nil, err
-	}
+// UpdateRoutingPolicy - Update a new Routing Policy
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateRoutingPolicy(ctx context.Context, RoutingPolicyId string, RoutingPolicy models.RoutingPolicyModifyItem, version string) (*models.RoutingPolicyResponse, error) {
+	return c.routingPolicies().Update(ctx, RoutingPolicyId, RoutingPolicy, version)
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/RoutingPolicies", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
+// DeleteRoutingPolicy - Delete a Routing Policy
+func (c *Client) DeleteRoutingPolicy(ctx context.Context, RoutingPolicyId string) (*models.RoutingPolicyResponse, error) {
+	return c.routingPolicies().Delete(ctx, RoutingPolicyId)
+}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// routingPolicyMatches reports whether an existing routing policy has the configuration
+// requested by want, including its rules in order and its edge services.
+func routingPolicyMatches(existing models.RoutingPolicyReadItem, want models.RoutingPolicyModifyItem) bool {
+	if existing.Name != want.Name ||
+		!refMatches(existing.VSlice.Id, existing.VSlice.Moniker, want.VSlice) ||
+		existing.RoutingPolicyStatus.Moniker != want.RoutingPolicyStatus ||
+		existing.RateLimitUplink.Moniker != want.RateLimitUplinkMoniker ||
+		existing.RateLimitDownlink.Moniker != want.RateLimitDownlinkMoniker ||
+		existing.PacketDiscardUplinkPercentage != want.PacketDiscardUplinkPercentage ||
+		existing.PacketDiscardDownlinkPercentage != want.PacketDiscardDownlinkPercentage {
+		return false
 	}
 
-	apiResponse := models.RoutingPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
+	rules := []models.Rule{}
+	if existing.RoutingPolicyRules != nil {
+		rules = *existing.RoutingPolicyRules
 	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
+	if len(rules) != len(want.RoutingPolicyRules) {
+		return false
 	}
-
-	return &apiResponse, nil
-}
-
-// UpdateRoutingPolicy - Update a new Routing Policy
-func (c *Client) UpdateRoutingPolicy(RoutingPolicyId string, RoutingPolicy models.RoutingPolicyModifyItem) (*models.RoutingPolicyResponse, error) {
-	rb, err := json.Marshal(RoutingPolicy)
-	if err != nil {
-		return nil, err
+	for i, rule := range rules {
+		if !ruleMatches(rule, want.RoutingPolicyRules[i]) {
+			return false
+		}
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/RoutingPolicies/%s", c.HostURL, RoutingPolicyId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
+	edgeServices := []models.EdgeService{}
+	if existing.RoutingPolicyEdgeServices != nil {
+		edgeServices = *existing.RoutingPolicyEdgeServices
 	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+	if len(edgeServices) != len(want.RoutingPolicyEdgeServices) {
+		return false
 	}
-
-	apiResponse := models.RoutingPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
+	for i, edgeService := range edgeServices {
+		if edgeService.Moniker != want.RoutingPolicyEdgeServices[i].Moniker || edgeService.Enabled != want.RoutingPolicyEdgeServices[i].Enabled {
+			return false
+		}
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+	return true
 }
 
-// DeleteRoutingPolicy - Delete a Routing Policy
-func (c *Client) DeleteRoutingPolicy(RoutingPolicyId string) (*models.RoutingPolicyResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/RoutingPolicies/%s", c.HostURL, RoutingPolicyId), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+func ruleMatches(existing models.Rule, want models.ModifyRule) bool {
+	transportProtocol := (*string)(nil)
+	if existing.TransportProtocol != nil {
+		transportProtocol = &existing.TransportProtocol.Moniker
 	}
 
-	apiResponse := models.RoutingPolicyResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
+	routingTargetMatches := want.RoutingTarget == nil && existing.RoutingTarget == nil
+	if want.RoutingTarget != nil && existing.RoutingTarget != nil {
+		routingTargetMatches = refMatches(existing.RoutingTarget.Id, existing.RoutingTarget.Moniker, *want.RoutingTarget)
 	}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
+	regionalGatewayMatches := want.RegionalGateway == nil && existing.RegionalGateway == nil
+	if want.RegionalGateway != nil && existing.RegionalGateway != nil {
+		regionalGatewayMatches = refMatches(existing.RegionalGateway.Id, existing.RegionalGateway.Moniker, *want.RegionalGateway)
 	}
 
-	return &apiResponse, nil
+	return existing.Description == want.Description &&
+		existing.RuleAction.Moniker == want.RuleAction &&
+		existing.RuleDirection.Moniker == want.RuleDirection &&
+		existing.Reflexive == want.Reflexive &&
+		existing.Enabled == want.Enabled &&
+		equalPtr(existing.SourceIpPattern, want.SourceIpPattern) &&
+		equalPtr(existing.DestinationIpPattern, want.DestinationIpPattern) &&
+		equalPtr(existing.SourcePortPattern, want.SourcePortPattern) &&
+		equalPtr(existing.DestinationPortPattern, want.DestinationPortPattern) &&
+		equalPtr(existing.DivertIp, want.DivertIp) &&
+		equalPtr(existing.DivertPort, want.DivertPort) &&
+		equalPtr(transportProtocol, want.TransportProtocol) &&
+		routingTargetMatches &&
+		regionalGatewayMatches
 }
@@ -3,152 +3,75 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
+	"iter"
+	"strconv"
 
-	"github.com/google/go-querystring/query"
 	"stacuity.com/go_client/models"
 )
 
-// GetRoutingTargets - Returns list of RoutingTargets
-func (c *Client) GetRoutingTargets(pagingState models.PagingState) ([]models.RoutingTargetReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	routingTargetItems := []models.RoutingTargetReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/routingtargets?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return routingTargetItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return routingTargetItems, err
-	}
+// routingTargets - Returns the collection backing the RoutingTarget methods
+func (c *Client) routingTargets() Collection[models.RoutingTargetReadItem, models.RoutingTargetModifyItem] {
+	return NewCollection[models.RoutingTargetReadItem, models.RoutingTargetModifyItem](c, "routingtargets")
+}
 
-	apiResponse := models.RoutingTargetList{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return routingTargetItems, err
-	}
+// GetRoutingTargets - Returns list of RoutingTargets
+func (c *Client) GetRoutingTargets(ctx context.Context, pagingState models.PagingState) ([]models.RoutingTargetReadItem, error) {
+	return c.routingTargets().List(ctx, pagingState)
+}
 
-	if !apiResponse.Success {
-		return routingTargetItems, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
+// GetRoutingTargetsPage - Returns a single page of RoutingTargets along with the paging totals
+func (c *Client) GetRoutingTargetsPage(ctx context.Context, pagingState models.PagingState) (models.RoutingTargetList, error) {
+	return c.routingTargets().ListPage(ctx, pagingState)
+}
 
-	routingTargetItems = append(routingTargetItems, apiResponse.Data...)
+// AllRoutingTargets - Iterates over all RoutingTargets matching pagingState, fetching pages as needed
+func (c *Client) AllRoutingTargets(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.RoutingTargetReadItem, error] {
+	return c.routingTargets().All(ctx, pagingState)
+}
 
-	return routingTargetItems, nil
+// GetAllRoutingTargets - Returns all RoutingTargets matching pagingState across all pages
+func (c *Client) GetAllRoutingTargets(ctx context.Context, pagingState models.PagingState) ([]models.RoutingTargetReadItem, error) {
+	return c.routingTargets().ListAll(ctx, pagingState)
 }
 
 // GetRoutingTarget - Returns a specific RoutingTarget
-func (c *Client) GetRoutingTarget(routingTargetId string) (models.RoutingTargetReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/routingtargets/%s", c.HostURL, routingTargetId), nil)
-	if err != nil {
-		return models.RoutingTargetReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.RoutingTargetReadItem{}, err
-	}
-
-	apiResponse := models.RoutingTargetSingle{}
-	err = json.Unmarshal(body, &apiResponse)
-
-	if err != nil {
-		return models.RoutingTargetReadItem{}, err
-	}
-
-	if !apiResponse.Success {
-		return models.RoutingTargetReadItem{}, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return apiResponse.Data, nil
+func (c *Client) GetRoutingTarget(ctx context.Context, routingTargetId string) (models.RoutingTargetReadItem, error) {
+	item, version, err := c.routingTargets().GetWithVersion(ctx, routingTargetId)
+	item.Version = version
+	return item, err
 }
 
-// CreateRoutingTarget - Create a new Routing Target
-func (c *Client) CreateRoutingTarget(routingTarget models.RoutingTargetModifyItem) (*models.RoutingTargetResponse, error) {
-	rb, err := json.Marshal(routingTarget)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/routingtargets", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.RoutingTargetResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// CreateRoutingTarget - Create a new Routing Target, adopting an existing target with the same moniker and configuration
+func (c *Client) CreateRoutingTarget(ctx context.Context, routingTarget models.RoutingTargetModifyItem) (*models.RoutingTargetResponse, error) {
+	return c.routingTargets().CreateOrAdopt(ctx, routingTarget, routingTarget.Moniker, routingTargetMatches, func(existing models.RoutingTargetReadItem) string {
+		return existing.Id
+	})
 }
 
 // UpdateRoutingTarget - Update a new Routing Target
-func (c *Client) UpdateRoutingTarget(routingTargetId string, routingTarget models.RoutingTargetModifyItem) (*models.RoutingTargetResponse, error) {
-	rb, err := json.Marshal(routingTarget)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/routingtargets/%s", c.HostURL, routingTargetId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.RoutingTargetResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateRoutingTarget(ctx context.Context, routingTargetId string, routingTarget models.RoutingTargetModifyItem, version string) (*models.RoutingTargetResponse, error) {
+	return c.routingTargets().Update(ctx, routingTargetId, routingTarget, version)
 }
 
 // DeleteRoutingTarget - Delete a Routing Target
-func (c *Client) DeleteRoutingTarget(routingTargetId string) (*models.RoutingTargetResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/routingtargets/%s", c.HostURL, routingTargetId), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.RoutingTargetResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) DeleteRoutingTarget(ctx context.Context, routingTargetId string) (*models.RoutingTargetResponse, error) {
+	return c.routingTargets().Delete(ctx, routingTargetId)
+}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
+// routingTargetMatches reports whether an existing routing target has the configuration requested
+// by want. Secrets in the configuration data are not returned by the API, so it is not compared.
+func routingTargetMatches(existing models.RoutingTargetReadItem, want models.RoutingTargetModifyItem) bool {
+	instanceId := ""
+	if existing.RoutingTargetTypeInstance.Id != 0 {
+		instanceId = strconv.Itoa(int(existing.RoutingTargetTypeInstance.Id))
 	}
 
-	return &apiResponse, nil
+	return existing.Name == want.Name &&
+		existing.RoutingTargetType.Moniker == want.RoutingTargetType &&
+		existing.RoutingRedundancyZoneMoniker == want.RoutingRedundancyZoneMoniker &&
+		refMatches(existing.VSlice.Id, existing.VSlice.Moniker, want.VSlice) &&
+		refMatches(instanceId, existing.RoutingTargetTypeInstance.Moniker, want.RoutingTargetTypeInstanceId)
 }
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// Throttle - Client-side limits shared by every request made through a Client. Terraform runs
+// several operations in parallel against the same Client, so these keep the combined traffic
+// under the API's rate limits.
+type Throttle struct {
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+// NewThrottle - Returns a Throttle allowing requestsPerSecond (token bucket, with a burst of
+// one second's worth of requests) and at most maxConcurrency requests in flight. A value of
+// zero or less disables the corresponding limit.
+func NewThrottle(requestsPerSecond float64, maxConcurrency int) *Throttle {
+	t := &Throttle{}
+
+	if requestsPerSecond > 0 {
+		burst := int(math.Ceil(requestsPerSecond))
+		t.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+
+	if maxConcurrency > 0 {
+		t.slots = make(chan struct{}, maxConcurrency)
+	}
+
+	return t
+}
+
+// acquire blocks until the request may be sent, returning a function that must be called
+// once the response has been read. It fails only if ctx is done first.
+func (t *Throttle) acquire(ctx context.Context) (func(), error) {
+	if t == nil {
+		return func() {}, nil
+	}
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if t.slots != nil {
+			<-t.slots
+		}
+	}
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
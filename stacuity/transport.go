@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+
+package stacuity
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultTimeout - Default time allowed for a single API request, including reading the response
+const DefaultTimeout = 30 * time.Second
+
+// TransportConfig - Settings for the HTTP client used to reach the Stacuity API
+type TransportConfig struct {
+	// Timeout for a single attempt. Zero uses DefaultTimeout.
+	Timeout time.Duration
+	// ProxyURL overrides the HTTPS_PROXY/HTTP_PROXY environment variables when set.
+	ProxyURL string
+	// CACertificatePEM is trusted in addition to the system roots.
+	CACertificatePEM []byte
+	// InsecureSkipVerify disables server certificate verification. Only for lab environments.
+	InsecureSkipVerify bool
+	// ClientCertificatePEM and ClientKeyPEM enable mutual TLS when both are set.
+	ClientCertificatePEM []byte
+	ClientKeyPEM         []byte
+}
+
+// NewHTTPClient - Returns an *http.Client configured from cfg
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Lab environments only; exposed through insecure_skip_verify on the provider.
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec
+	}
+
+	if len(cfg.CACertificatePEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(cfg.CACertificatePEM) {
+			return nil, errors.New("no certificates could be parsed from the CA certificate bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(cfg.ClientCertificatePEM) > 0 || len(cfg.ClientKeyPEM) > 0 {
+		if len(cfg.ClientCertificatePEM) == 0 || len(cfg.ClientKeyPEM) == 0 {
+			return nil, errors.New("client certificate and client key must be provided together")
+		}
+		certificate, err := tls.X509KeyPair(cfg.ClientCertificatePEM, cfg.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
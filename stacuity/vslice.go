@@ -3,154 +3,73 @@
 package stacuity
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
+	"iter"
+	"slices"
 
 	"stacuity.com/go_client/models"
-
-	"github.com/google/go-querystring/query"
 )
 
-// GetVSlices - Returns list of VSlices
-func (c *Client) GetVSlices(pagingState models.PagingState) ([]models.VSliceReadItem, error) {
-	querystring, _ := query.Values(pagingState)
-	vSliceItems := []models.VSliceReadItem{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/vslices?", c.HostURL)+querystring.Encode(), nil)
-	if err != nil {
-		return vSliceItems, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return vSliceItems, err
-	}
-
-	vSlices := models.VSliceList{}
-	err = json.Unmarshal(body, &vSlices)
+// vSlices - Returns the collection backing the VSlice methods
+func (c *Client) vSlices() Collection[models.VSliceReadItem, models.VSliceModifyItem] {
+	return NewCollection[models.VSliceReadItem, models.VSliceModifyItem](c, "vslices")
+}
 
-	if err != nil {
-		return vSliceItems, err
-	}
+// GetVSlices - Returns list of VSlices
+func (c *Client) GetVSlices(ctx context.Context, pagingState models.PagingState) ([]models.VSliceReadItem, error) {
+	return c.vSlices().List(ctx, pagingState)
+}
 
-	if !vSlices.Success {
-		return vSliceItems, errors.New(strings.Join(vSlices.Messages, " "))
-	}
+// GetVSlicesPage - Returns a single page of VSlices along with the paging totals
+func (c *Client) GetVSlicesPage(ctx context.Context, pagingState models.PagingState) (models.VSliceList, error) {
+	return c.vSlices().ListPage(ctx, pagingState)
+}
 
-	vSliceItems = append(vSliceItems, vSlices.Data...)
+// AllVSlices - Iterates over all VSlices matching pagingState, fetching pages as needed
+func (c *Client) AllVSlices(ctx context.Context, pagingState models.PagingState) iter.Seq2[models.VSliceReadItem, error] {
+	return c.vSlices().All(ctx, pagingState)
+}
 
-	return vSliceItems, nil
+// GetAllVSlices - Returns all VSlices matching pagingState across all pages
+func (c *Client) GetAllVSlices(ctx context.Context, pagingState models.PagingState) ([]models.VSliceReadItem, error) {
+	return c.vSlices().ListAll(ctx, pagingState)
 }
 
 // GetVSlice - Returns a specific VSlice
-func (c *Client) GetVSlice(vSliceId string) (models.VSliceReadItem, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/vslices/%s", c.HostURL, vSliceId), nil)
-	if err != nil {
-		return models.VSliceReadItem{}, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return models.VSliceReadItem{}, err
-	}
-
-	vSlices := models.VSliceSingle{}
-	err = json.Unmarshal(body, &vSlices)
-
-	if err != nil {
-		return models.VSliceReadItem{}, err
-	}
-
-	if !vSlices.Success {
-		return models.VSliceReadItem{}, errors.New(strings.Join(vSlices.Messages, " "))
-	}
-
-	return vSlices.Data, nil
+func (c *Client) GetVSlice(ctx context.Context, vSliceId string) (models.VSliceReadItem, error) {
+	item, version, err := c.vSlices().GetWithVersion(ctx, vSliceId)
+	item.Version = version
+	return item, err
 }
 
-// CreateVSlice - Create a new vSlice
-func (c *Client) CreateVSlice(vSlice models.VSliceModifyItem) (*models.VSliceResponse, error) {
-	rb, err := json.Marshal(vSlice)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/vslices", c.HostURL), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.VSliceResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// CreateVSlice - Create a new vSlice, adopting an existing vSlice with the same moniker and configuration
+func (c *Client) CreateVSlice(ctx context.Context, vSlice models.VSliceModifyItem) (*models.VSliceResponse, error) {
+	return c.vSlices().CreateOrAdopt(ctx, vSlice, vSlice.Moniker, vSliceMatches, func(existing models.VSliceReadItem) string {
+		return existing.Id
+	})
 }
 
 // UpdateVSlice - Update a new vSlice
-func (c *Client) UpdateVSlice(vSliceId string, vSlice models.VSliceModifyItem) (*models.VSliceResponse, error) {
-	rb, err := json.Marshal(vSlice)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/vslices/%s", c.HostURL, vSliceId), strings.NewReader(string(rb)))
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.VSliceResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
-
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
-	}
-
-	return &apiResponse, nil
+// Unless version is empty, the update fails with ErrPreconditionFailed when the object has
+// changed since it was read with that version.
+func (c *Client) UpdateVSlice(ctx context.Context, vSliceId string, vSlice models.VSliceModifyItem, version string) (*models.VSliceResponse, error) {
+	return c.vSlices().Update(ctx, vSliceId, vSlice, version)
 }
 
 // DeleteVSlice - Delete a vSlice
-func (c *Client) DeleteVSlice(vSliceId string) (*models.VSliceResponse, error) {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/vslices/%s", c.HostURL, vSliceId), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	apiResponse := models.VSliceResponse{}
-	err = json.Unmarshal(body, &apiResponse)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) DeleteVSlice(ctx context.Context, vSliceId string) (*models.VSliceResponse, error) {
+	return c.vSlices().Delete(ctx, vSliceId)
+}
 
-	if !apiResponse.Success {
-		return nil, errors.New(strings.Join(apiResponse.Messages, " "))
+// vSliceMatches reports whether an existing vSlice has the configuration requested by want.
+func vSliceMatches(existing models.VSliceReadItem, want models.VSliceModifyItem) bool {
+	if want.SubnetAddress != "" && !slices.Contains(existing.Subnets, want.SubnetAddress) {
+		return false
 	}
 
-	return &apiResponse, nil
+	return existing.Name == want.Name &&
+		existing.DNSMode.Moniker == want.DNSMode &&
+		existing.IpAddressFamily.Moniker == want.IpAddressFamily &&
+		refMatches(existing.EventMap.Id, existing.EventMap.Moniker, want.EventMap) &&
+		sameElements(existing.DNSServers, want.DNSServers)
 }